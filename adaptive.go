@@ -0,0 +1,127 @@
+package clarifai
+
+import (
+	"sync"
+	"time"
+)
+
+// AdaptiveBatchSizer sizes the chunks requests are split into by the latency and
+// failures of the requests already sent, aiming for each request to take Target.
+// A request that takes longer than Target, or fails with a network or server error,
+// shrinks the next chunks; faster requests grow them. The size stays within [Min, Max]
+// and never exceeds the API's MaxBatchSize. It is safe for concurrent use.
+// A sizer built as a struct literal starts at MaxBatchSize, or Max when the API
+// sets no batch limit, within its bounds.
+type AdaptiveBatchSizer struct {
+	// Min and Max bound the batch size; a Min below 1 means 1 and a zero Max
+	// leaves the size bounded only by MaxBatchSize
+	Min int
+	Max int
+	// Target is the per-request latency the batch size is adjusted toward
+	Target time.Duration
+
+	mu   sync.Mutex
+	size int
+}
+
+// NewAdaptiveBatchSizer returns a sizer that starts at start inputs per request and
+// adjusts between min and max toward target. min is at least 1 and max at least min.
+func NewAdaptiveBatchSizer(start, min, max int, target time.Duration) *AdaptiveBatchSizer {
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+
+	s := &AdaptiveBatchSizer{Min: min, Max: max, Target: target}
+	s.size = s.clamp(start)
+	return s
+}
+
+// Size returns the number of inputs the next request will be split by,
+// zero for a struct literal that has not split a request yet
+func (s *AdaptiveBatchSizer) Size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// Helper function to get the size to split by, starting at limit if the sizer has no size yet
+func (s *AdaptiveBatchSizer) sizeFor(limit int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size == 0 {
+		start := limit
+		if start <= 0 {
+			start = s.Max
+		}
+		s.size = s.clamp(start)
+	}
+	return s.size
+}
+
+// Helper function to keep a batch size within the sizer's bounds
+func (s *AdaptiveBatchSizer) clamp(size int) int {
+	min := s.Min
+	if min < 1 {
+		min = 1
+	}
+
+	if size < min {
+		return min
+	}
+	if s.Max >= min && size > s.Max {
+		return s.Max
+	}
+	return size
+}
+
+// Helper function to adjust the size after a request of inputs inputs that took elapsed.
+// Failures halve the size. Otherwise it moves halfway toward the number of inputs the
+// observed per-input latency would fit in Target.
+func (s *AdaptiveBatchSizer) observe(inputs int, elapsed time.Duration, err error) {
+	if inputs <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		switch CategorizeError(err) {
+		case FailureNetwork, FailureServer:
+			s.size = s.clamp(s.size / 2)
+		}
+		return
+	}
+
+	if elapsed <= 0 || s.Target <= 0 {
+		return
+	}
+
+	ideal := int(int64(s.Target) * int64(inputs) / int64(elapsed))
+	s.size = s.clamp((s.size + ideal) / 2)
+}
+
+// Helper function to get the batch size requests are split by: the adaptive size when
+// BatchSizer is set, capped by the limit, or the limit itself
+func (client *Client) batchSize(limit int) int {
+	if client.BatchSizer == nil {
+		return limit
+	}
+
+	size := client.BatchSizer.sizeFor(limit)
+	if limit > 0 && size > limit {
+		return limit
+	}
+	return size
+}
+
+// Helper function to report a request's latency and outcome to the BatchSizer, if any
+func (client *Client) observeBatch(inputs int, elapsed time.Duration, err error) {
+	if client.BatchSizer != nil {
+		client.BatchSizer.observe(inputs, elapsed, err)
+	}
+}
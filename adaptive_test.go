@@ -0,0 +1,117 @@
+package clarifai
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveBatchSizerObserve(t *testing.T) {
+	s := NewAdaptiveBatchSizer(10, 2, 40, time.Second)
+
+	steps := []struct {
+		name    string
+		inputs  int
+		elapsed time.Duration
+		err     error
+		size    int
+	}{
+		{"fast request grows", 10, 500 * time.Millisecond, nil, 15},
+		{"slow request shrinks", 15, 3 * time.Second, nil, 10},
+		{"server error halves", 10, time.Second, &APIError{HTTPStatus: 500}, 5},
+		{"client error is ignored", 5, time.Second, &APIError{HTTPStatus: 400}, 5},
+		{"never below min", 5, time.Second, &APIError{HTTPStatus: 503}, 2},
+		{"never above max", 2, time.Millisecond, nil, 40},
+	}
+
+	for _, step := range steps {
+		s.observe(step.inputs, step.elapsed, step.err)
+		if got := s.Size(); got != step.size {
+			t.Errorf("%s: expected size %d, got %d", step.name, step.size, got)
+		}
+	}
+
+	if s := NewAdaptiveBatchSizer(100, 0, 0, time.Second); s.Min != 1 || s.Max != 1 || s.Size() != 1 {
+		t.Errorf("Bounds should be at least one input, got min %d, max %d, size %d", s.Min, s.Max, s.Size())
+	}
+}
+
+func TestAdaptiveBatchSizing(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 3
+	client.BatchSizer = NewAdaptiveBatchSizer(10, 1, 10, time.Minute)
+
+	defer server.Close()
+
+	var mu sync.Mutex
+	var sizes []int
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		var req TagRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		sizes = append(sizes, len(req.URLs))
+		mu.Unlock()
+		w.WriteHeader(500)
+	})
+
+	client.Tag(TagRequest{URLs: []string{"a", "b", "c", "d"}})
+
+	// The adaptive size is still capped by the API's batch size
+	if len(sizes) != 2 || sizes[0]+sizes[1] != 4 || (sizes[0] != 3 && sizes[1] != 3) {
+		t.Errorf("Four urls should be split in chunks of at most three, got %v", sizes)
+	}
+
+	// Both failed chunks halved the size
+	if size := client.Config().AdaptiveBatchSize; size != 2 {
+		t.Errorf("Failed requests should shrink the batch size to 2, got %d", size)
+	}
+
+	sizes = nil
+	client.Tag(TagRequest{URLs: []string{"a", "b", "c", "d"}})
+
+	if len(sizes) != 2 || sizes[0] != 2 || sizes[1] != 2 {
+		t.Errorf("The next request should be split by the adapted size, got %v", sizes)
+	}
+}
+
+func TestAdaptiveBatchSizerLiteral(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 2
+	client.BatchSizer = &AdaptiveBatchSizer{Min: 1, Max: 10, Target: time.Minute}
+
+	defer server.Close()
+
+	var mu sync.Mutex
+	var sizes []int
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		var req TagRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		sizes = append(sizes, len(req.URLs))
+		mu.Unlock()
+		echoTagHandler(w, r)
+	})
+
+	if _, err := client.Tag(TagRequest{URLs: []string{"a", "b", "c", "d", "e"}}); err != nil {
+		t.Fatalf("Tag() should not return error: %v", err)
+	}
+
+	// A sizer without a starting size begins at the batch limit instead of sending everything at once
+	if len(sizes) != 3 {
+		t.Errorf("Five urls should be split in chunks of at most two, got %v", sizes)
+	}
+	for _, size := range sizes {
+		if size > 2 {
+			t.Errorf("No chunk should exceed the batch limit, got %v", sizes)
+		}
+	}
+}
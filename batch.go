@@ -0,0 +1,125 @@
+package clarifai
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// ModelInput is a single image to tag with its own model
+type ModelInput struct {
+	URL     string
+	LocalID string
+	Model   string
+}
+
+// Provenance identifies the request within a merged batch that produced a result
+type Provenance struct {
+	Chunk     int
+	RequestID string
+}
+
+// BatchByModel tags images that need different models by sending one request per
+// model concurrently, then merging the results back into the order of items.
+// Requests are correlated through their local ids; the caller's LocalIDs are restored.
+// If any request fails a *BatchError summarizing the failures is returned.
+func (client *Client) BatchByModel(items []ModelInput, opts ...RequestOption) (*TagResp, error) {
+	o := newRequestOptions(opts)
+	defer o.progress.close()
+
+	if len(items) < 1 {
+		return nil, errors.New("Requires at least one url")
+	}
+
+	if err := o.ensureIdempotencyKey(); err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]int)
+	var models []string
+	for i, item := range items {
+		if _, ok := groups[item.Model]; !ok {
+			models = append(models, item.Model)
+		}
+		groups[item.Model] = append(groups[item.Model], i)
+	}
+
+	responses := make([]*TagResp, len(models))
+	requests := make([]TagRequest, len(models))
+	errs := make([]error, len(models))
+	var wg sync.WaitGroup
+	// Each model's request is reported as one chunk of the batch rather than by its own chunks
+	groupOpts := append(opts[:len(opts):len(opts)], withoutProgress)
+
+	for g, model := range models {
+		req := TagRequest{Model: model}
+		for _, i := range groups[model] {
+			req.URLs = append(req.URLs, items[i].URL)
+			req.LocalIDs = append(req.LocalIDs, strconv.Itoa(i))
+		}
+		requests[g] = req
+
+		// Each model's request has its own body, so it gets the call's key with its own suffix
+		reqOpts := groupOpts
+		if o.idempotent {
+			reqOpts = append(groupOpts[:len(groupOpts):len(groupOpts)], WithIdempotencyKey(o.idempotencyKey+"-"+strconv.Itoa(g)))
+		}
+
+		wg.Add(1)
+		go func(g int, req TagRequest, reqOpts []RequestOption) {
+			defer wg.Done()
+			o.progress.track(g, len(models), len(req.URLs), func() error {
+				responses[g], errs[g] = client.Tag(req, reqOpts...)
+				return errs[g]
+			})
+		}(g, req, reqOpts)
+	}
+
+	wg.Wait()
+
+	if err := newBatchError(errs); err != nil {
+		return nil, err
+	}
+
+	merged := mergeByLocalIndex(items, responses)
+
+	if o.provenance {
+		for g, req := range requests {
+			id := RequestID(req)
+			for _, i := range groups[req.Model] {
+				merged.Results[i].Provenance = &Provenance{Chunk: g, RequestID: id}
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+// Helper function to place results tagged with their input index as local id back in input order
+func mergeByLocalIndex(items []ModelInput, responses []*TagResp) *TagResp {
+	merged := &TagResp{StatusCode: StatusOK, Results: make([]TagResult, len(items))}
+	found := make([]bool, len(items))
+
+	for _, resp := range responses {
+		for _, result := range resp.Results {
+			i, err := strconv.Atoi(result.LocalID)
+			if err != nil || i < 0 || i >= len(items) {
+				continue
+			}
+			result.LocalID = items[i].LocalID
+			merged.Results[i] = result
+			found[i] = true
+		}
+	}
+
+	for i, item := range items {
+		if !found[i] {
+			merged.Results[i] = TagResult{URL: item.URL, LocalID: item.LocalID, StatusCode: StatusClientError, StatusMessage: "Missing from response", Model: item.Model}
+		}
+		if merged.Results[i].StatusCode.IsError() {
+			merged.StatusCode = StatusPartialError
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,147 @@
+package clarifai
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// echoTagHandler answers a tag request with one OK result per url, tagged with the requested model
+func echoTagHandler(w http.ResponseWriter, r *http.Request) {
+	var req TagRequest
+	json.NewDecoder(r.Body).Decode(&req)
+
+	resp := TagResp{StatusCode: StatusOK}
+	resp.Meta.Tag.Model = req.Model
+	for i, u := range req.URLs {
+		result := TagResult{URL: u, StatusCode: StatusOK}
+		if i < len(req.LocalIDs) {
+			result.LocalID = req.LocalIDs[i]
+		}
+		result.Result.Tag.Classes = []string{req.Model}
+		result.Result.Tag.Probs = []float32{1}
+		resp.Results = append(resp.Results, result)
+	}
+
+	w.WriteHeader(200)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func TestBatchByModel(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", echoTagHandler)
+
+	items := []ModelInput{
+		{URL: "http://a", Model: "general"},
+		{URL: "http://b", Model: "nsfw", LocalID: "mine"},
+		{URL: "http://c", Model: "general"},
+	}
+	tagres, err := client.BatchByModel(items)
+
+	if err != nil {
+		t.Fatalf("BatchByModel() should not return an err: %v", err)
+	}
+
+	for i, item := range items {
+		result := tagres.Results[i]
+		if result.URL != item.URL || result.LocalID != item.LocalID || result.Result.Tag.Classes[0] != item.Model {
+			t.Errorf("BatchByModel() should keep result %d aligned with its input. Got: %+v", i, result)
+		}
+	}
+}
+
+func TestBatchByModelError(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+		fmt.Fprintln(w, `{}`)
+	})
+
+	_, err := client.BatchByModel([]ModelInput{{URL: "http://a", Model: "general"}})
+
+	if err == nil {
+		t.Error("BatchByModel() should return an err when a request fails")
+	}
+}
+
+func TestBatchByModelProvenance(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", echoTagHandler)
+
+	items := []ModelInput{{URL: "http://a", Model: "general"}, {URL: "http://b", Model: "nsfw"}, {URL: "http://c", Model: "general"}}
+
+	tagres, err := client.BatchByModel(items)
+
+	if err != nil || tagres.Results[0].Provenance != nil {
+		t.Errorf("BatchByModel() should not record provenance by default. Got: %v, %v", tagres.Results[0].Provenance, err)
+	}
+
+	tagres, err = client.BatchByModel(items, WithProvenance())
+
+	if err != nil {
+		t.Fatalf("BatchByModel() should not return an err: %v", err)
+	}
+
+	var p []Provenance
+	for _, result := range tagres.Results {
+		if result.Provenance == nil {
+			t.Fatalf("BatchByModel() should record provenance on every result")
+		}
+		p = append(p, *result.Provenance)
+	}
+	if p[0] != p[2] || p[0].Chunk == p[1].Chunk || p[0].RequestID == "" {
+		t.Errorf("BatchByModel() should record which request produced each result. Got: %+v", p)
+	}
+
+	// Provenance travels with its result when results are reordered or sampled
+	sampled := tagres.Sample(1, 1)
+	if sampled.Results[0].Provenance == nil {
+		t.Error("Sample() should keep the provenance of the sampled result")
+	}
+}
+
+func TestBatchByModelIdempotencyKeys(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	keys := make(chan string, 2)
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		keys <- r.Header.Get("Idempotency-Key")
+		echoTagHandler(w, r)
+	})
+
+	items := []ModelInput{{URL: "a", Model: "general"}, {URL: "b", Model: "nsfw"}}
+	if _, err := client.BatchByModel(items, WithIdempotencyKey("k")); err != nil {
+		t.Fatalf("BatchByModel() should not return error: %v", err)
+	}
+
+	first, second := <-keys, <-keys
+	if first == second || !strings.HasPrefix(first, "k-") || !strings.HasPrefix(second, "k-") {
+		t.Errorf("Each model's request should get the key with its own suffix, got %q and %q", first, second)
+	}
+}
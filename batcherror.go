@@ -0,0 +1,127 @@
+package clarifai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// FailureCategory is the broad cause of a failed request
+type FailureCategory int
+
+// Failure categories reported by CategorizeError
+const (
+	FailureOther FailureCategory = iota
+	FailureNetwork
+	FailureClient
+	FailureServer
+)
+
+func (c FailureCategory) String() string {
+	switch c {
+	case FailureNetwork:
+		return "network"
+	case FailureClient:
+		return "4xx"
+	case FailureServer:
+		return "5xx"
+	default:
+		return "other"
+	}
+}
+
+// CategorizeError reports whether err came from the network, from the API rejecting
+// the request (4xx) or from the API failing to process it (5xx)
+func CategorizeError(err error) FailureCategory {
+	var apiErr *APIError
+	var netErr net.Error
+
+	switch {
+	case errors.As(err, &apiErr):
+		if apiErr.HTTPStatus >= 500 {
+			return FailureServer
+		}
+		if apiErr.HTTPStatus >= 400 {
+			return FailureClient
+		}
+		return FailureOther
+	case errors.As(err, &netErr), errors.Is(err, context.DeadlineExceeded):
+		return FailureNetwork
+	default:
+		return FailureOther
+	}
+}
+
+// batchErrorSamples is how many underlying errors a BatchError message includes
+const batchErrorSamples = 3
+
+// BatchError is returned when requests of a concurrent batch fail.
+// It summarizes the failures by category and unwraps to every one of them.
+type BatchError struct {
+	// Errors holds the error of every failed request, in request order
+	Errors []error
+	// Requests is the number of requests the batch sent
+	Requests int
+}
+
+func (e *BatchError) Error() string {
+	var counts []string
+	for _, c := range []FailureCategory{FailureNetwork, FailureClient, FailureServer, FailureOther} {
+		if n := e.Count(c); n > 0 {
+			counts = append(counts, fmt.Sprintf("%s: %d", c, n))
+		}
+	}
+
+	var samples []string
+	for i, err := range e.Errors {
+		if i == batchErrorSamples {
+			samples = append(samples, "...")
+			break
+		}
+		samples = append(samples, err.Error())
+	}
+
+	return fmt.Sprintf("%d of %d batch requests failed (%s): %s", len(e.Errors), e.Requests, strings.Join(counts, ", "), strings.Join(samples, "; "))
+}
+
+// Unwrap returns every underlying error, for errors.Is and errors.As
+func (e *BatchError) Unwrap() []error {
+	return e.Errors
+}
+
+// Count returns how many of the failures fall in category
+func (e *BatchError) Count(category FailureCategory) int {
+	n := 0
+	for _, err := range e.Errors {
+		if CategorizeError(err) == category {
+			n++
+		}
+	}
+	return n
+}
+
+// Counts returns the number of failures in each category that has any
+func (e *BatchError) Counts() map[FailureCategory]int {
+	counts := make(map[FailureCategory]int)
+	for _, err := range e.Errors {
+		counts[CategorizeError(err)]++
+	}
+	return counts
+}
+
+// Helper function to collect the failures of a batch, nil if every request succeeded
+func newBatchError(errs []error) error {
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+
+	if failed == nil {
+		return nil
+	}
+	return &BatchError{Errors: failed, Requests: len(errs)}
+}
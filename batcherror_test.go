@@ -0,0 +1,80 @@
+package clarifai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCategorizeError(t *testing.T) {
+	cases := []struct {
+		err      error
+		category FailureCategory
+	}{
+		{&APIError{HTTPStatus: 400, StatusCode: StatusAllError}, FailureClient},
+		{&APIError{HTTPStatus: 500, StatusCode: StatusClarifaiError}, FailureServer},
+		{context.DeadlineExceeded, FailureNetwork},
+		{errors.New("Requires at least one url"), FailureOther},
+	}
+
+	for _, c := range cases {
+		if got := CategorizeError(c.err); got != c.category {
+			t.Errorf("CategorizeError(%v) should be %s, got %s", c.err, c.category, got)
+		}
+	}
+}
+
+func TestBatchError(t *testing.T) {
+	err := newBatchError([]error{
+		nil,
+		&APIError{HTTPStatus: 500, StatusCode: StatusClarifaiError},
+		&APIError{HTTPStatus: 429, StatusCode: StatusThrottled},
+		context.DeadlineExceeded,
+		&APIError{HTTPStatus: 500, StatusCode: StatusClarifaiError},
+		errors.New("boom"),
+	})
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("newBatchError() should return a *BatchError, got %v", err)
+	}
+
+	if batchErr.Count(FailureServer) != 2 || batchErr.Count(FailureClient) != 1 || batchErr.Counts()[FailureNetwork] != 1 {
+		t.Errorf("Unexpected counts: %v", batchErr.Counts())
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("BatchError should unwrap to every failure")
+	}
+
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "5 of 6 batch requests failed (network: 1, 4xx: 1, 5xx: 2, other: 1)") || !strings.HasSuffix(msg, "; ...") {
+		t.Errorf("Unexpected message: %s", msg)
+	}
+
+	if newBatchError([]error{nil, nil}) != nil {
+		t.Error("newBatchError() should return nil when nothing failed")
+	}
+}
+
+func TestBatchByModelBatchError(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	})
+
+	_, err := client.BatchByModel([]ModelInput{{URL: "a", Model: "general"}, {URL: "b", Model: "nsfw"}})
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Count(FailureServer) != 2 || batchErr.Requests != 2 {
+		t.Errorf("BatchByModel() should summarize every failed request, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package clarifai
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// ErrDecompressedTooLarge is returned when a response body exceeds Client.MaxDecompressedBytes
+var ErrDecompressedTooLarge = errors.New("DECOMPRESSED_TOO_LARGE")
+
+// Helper function to read a response body, decompressing gzip the transport left
+// encoded and enforcing the client's decompressed size limit
+func (client *Client) readBody(res *http.Response) ([]byte, error) {
+	var body io.Reader = res.Body
+
+	// The transport only leaves this header when it did not decompress the body itself
+	if res.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(res.Body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer gz.Close()
+		body = gz
+	}
+
+	if client.MaxDecompressedBytes <= 0 {
+		return ioutil.ReadAll(body)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(body, client.MaxDecompressedBytes+1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > client.MaxDecompressedBytes {
+		return nil, ErrDecompressedTooLarge
+	}
+
+	return data, nil
+}
@@ -0,0 +1,69 @@
+package clarifai
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadBodyGzip(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		buf := new(bytes.Buffer)
+		gz := gzip.NewWriter(buf)
+		fmt.Fprint(gz, `{"status_code":"OK","status_msg":"ok","results":{"default_language":"en"}}`)
+		gz.Close()
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(200)
+		w.Write(buf.Bytes())
+	})
+
+	// Asking for gzip explicitly stops the transport from decoding it for us
+	client.HTTPClient = &http.Client{Transport: headerTransport{"Accept-Encoding", "gzip"}}
+	info, err := client.Info()
+
+	if err != nil || info.Results.DefaultLanguage != "en" {
+		t.Errorf("Info() should decode a gzip response. Got: %+v, %v", info, err)
+	}
+}
+
+func TestMaxDecompressedBytes(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.MaxDecompressedBytes = 64
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `{"status_code":"OK","status_msg":"`+strings.Repeat("x", 128)+`"}`)
+	})
+
+	_, err := client.Info()
+
+	if err != ErrDecompressedTooLarge {
+		t.Errorf("Info() should return ErrDecompressedTooLarge for an oversized body. Got: %v", err)
+	}
+}
+
+// headerTransport sets a header on every request before sending it
+type headerTransport struct {
+	key, value string
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.Header.Set(t.key, t.value)
+	return http.DefaultTransport.RoundTrip(req)
+}
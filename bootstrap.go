@@ -0,0 +1,41 @@
+package clarifai
+
+import (
+	"context"
+	"time"
+)
+
+// BootstrapSummary describes the API the client connected to at startup
+type BootstrapSummary struct {
+	APIRoot         string        `json:"api_root"`
+	APIVersion      float32       `json:"api_version"`
+	DefaultModel    string        `json:"default_model"`
+	DefaultLanguage string        `json:"default_language"`
+	Limits          Limits        `json:"limits"`
+	Duration        time.Duration `json:"duration"`
+}
+
+// Bootstrap verifies the client's credentials and caches the API limits by
+// calling /info/, returning a summary suitable for logging at startup.
+// Authentication failures are returned immediately.
+func (client *Client) Bootstrap(ctx context.Context) (*BootstrapSummary, error) {
+	start := time.Now()
+
+	// /info/ refreshes the access token on its way, so a failure here covers bad credentials too
+	info, err := client.info(ctx, &requestOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	limits := client.setLimits(infoLimits(info))
+
+	return &BootstrapSummary{
+		APIRoot:         client.APIRoot,
+		APIVersion:      info.Results.APIVersion,
+		DefaultModel:    info.Results.DefaultModel,
+		DefaultLanguage: info.Results.DefaultLanguage,
+		Limits:          limits,
+		Duration:        time.Since(start),
+	}, nil
+}
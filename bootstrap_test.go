@@ -0,0 +1,70 @@
+package clarifai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBootstrap(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"access_token":"1234567890abcdefg","expires_in":36000,"scope": "api_access", "token_type": "Bearer"}`)
+	})
+
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer 1234567890abcdefg" {
+			w.WriteHeader(401)
+			return
+		}
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":{"default_model":"default","default_language":"en","max_batch_size":64,"api_version":0.1}}`)
+	})
+
+	summary, err := client.Bootstrap(context.Background())
+
+	if err != nil {
+		t.Fatalf("Bootstrap() should not return an err with valid credentials: %v", err)
+	}
+
+	if summary.DefaultModel != "default" || summary.Limits.MaxBatchSize != 64 || summary.Limits.MaxImageBytes != DefaultMaxImageBytes {
+		t.Errorf("Bootstrap() should summarize /info/. Got: %+v", summary)
+	}
+
+	if client.Limits().MaxBatchSize != 64 {
+		t.Error("Bootstrap() should cache the limits")
+	}
+}
+
+func TestBootstrapAuthFailure(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(401)
+		fmt.Fprintln(w, `{}`)
+	})
+
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(401)
+	})
+
+	_, err := client.Bootstrap(context.Background())
+
+	if err == nil {
+		t.Error("Bootstrap() should return an err with invalid credentials")
+	}
+}
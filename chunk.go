@@ -0,0 +1,43 @@
+package clarifai
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// span is a contiguous [start, end) range of inputs sent in one request
+type span struct {
+	start, end int
+}
+
+// chunkImages packs images, in order, into contiguous spans that hold at most
+// maxCount images and at most maxBytes of base64 encoded data each. Keeping the
+// spans contiguous preserves the positional alignment of LocalIDs. A
+// non-positive limit is treated as unlimited.
+func chunkImages(images [][]byte, maxCount, maxBytes int) ([]span, error) {
+	var spans []span
+	start, size := 0, 0
+
+	for i, image := range images {
+		encoded := base64.StdEncoding.EncodedLen(len(image))
+
+		if maxBytes > 0 && encoded > maxBytes {
+			return nil, fmt.Errorf("Image %d is %d bytes encoded, which exceeds the %d byte limit", i, encoded, maxBytes)
+		}
+
+		full := maxCount > 0 && i-start >= maxCount
+		over := maxBytes > 0 && size+encoded > maxBytes
+		if i > start && (full || over) {
+			spans = append(spans, span{start, i})
+			start, size = i, 0
+		}
+
+		size += encoded
+	}
+
+	if start < len(images) {
+		spans = append(spans, span{start, len(images)})
+	}
+
+	return spans, nil
+}
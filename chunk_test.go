@@ -0,0 +1,67 @@
+package clarifai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChunkImagesCountLimit(t *testing.T) {
+	images := [][]byte{{1}, {2}, {3}, {4}, {5}}
+
+	spans, err := chunkImages(images, 2, 0)
+
+	if err != nil {
+		t.Errorf("chunkImages() should not return an err: %v", err)
+	}
+
+	expected := []span{{0, 2}, {2, 4}, {4, 5}}
+	if !reflect.DeepEqual(spans, expected) {
+		t.Errorf("chunkImages() should respect the count limit. Expected: %v, Got: %v", expected, spans)
+	}
+}
+
+func TestChunkImagesByteLimit(t *testing.T) {
+	// 3 raw bytes encode to exactly 4 base64 bytes, 4 raw bytes to 8
+	images := [][]byte{
+		make([]byte, 3),
+		make([]byte, 3),
+		make([]byte, 4),
+		make([]byte, 6),
+		make([]byte, 1),
+	}
+
+	spans, err := chunkImages(images, 10, 8)
+
+	if err != nil {
+		t.Errorf("chunkImages() should not return an err: %v", err)
+	}
+
+	// 4+4 fills the first chunk exactly, 8 fills the second, 8 the third, 4 the last
+	expected := []span{{0, 2}, {2, 3}, {3, 4}, {4, 5}}
+	if !reflect.DeepEqual(spans, expected) {
+		t.Errorf("chunkImages() should respect the byte limit. Expected: %v, Got: %v", expected, spans)
+	}
+}
+
+func TestChunkImagesBothLimits(t *testing.T) {
+	images := [][]byte{make([]byte, 3), make([]byte, 3), make([]byte, 3), make([]byte, 9)}
+
+	spans, err := chunkImages(images, 2, 12)
+
+	if err != nil {
+		t.Errorf("chunkImages() should not return an err: %v", err)
+	}
+
+	expected := []span{{0, 2}, {2, 3}, {3, 4}}
+	if !reflect.DeepEqual(spans, expected) {
+		t.Errorf("chunkImages() should respect both limits. Expected: %v, Got: %v", expected, spans)
+	}
+}
+
+func TestChunkImagesOversized(t *testing.T) {
+	_, err := chunkImages([][]byte{make([]byte, 7)}, 10, 8)
+
+	if err == nil {
+		t.Error("chunkImages() should return an err when a single image exceeds the byte limit")
+	}
+}
@@ -0,0 +1,290 @@
+package clarifai
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+)
+
+// ChunkFailure is a chunk of an oversized request that could not be completed
+type ChunkFailure struct {
+	// Start and End are the [Start, End) input indexes the chunk covered
+	Start int
+	End   int
+	URLs  []string
+	Err   error
+}
+
+// ChunkError is returned when some chunks of an automatically split request fail.
+// The results of the chunks that succeeded are kept in TagResp or ColorResp, in
+// input order, with placeholders at the positions of the failed inputs.
+type ChunkError struct {
+	Failed    []ChunkFailure
+	TagResp   *TagResp
+	ColorResp *ColorResp
+
+	// Chunks is the number of chunks the request was split into
+	Chunks int
+}
+
+func (e *ChunkError) Error() string {
+	return e.Batch().Error()
+}
+
+// Unwrap returns the error of every failed chunk
+func (e *ChunkError) Unwrap() []error {
+	return e.Batch().Errors
+}
+
+// Batch summarizes the chunk failures as a BatchError
+func (e *ChunkError) Batch() *BatchError {
+	errs := make([]error, len(e.Failed))
+	for i, failure := range e.Failed {
+		errs[i] = failure.Err
+	}
+	return &BatchError{Errors: errs, Requests: e.Chunks}
+}
+
+// DefaultChunkConcurrency is how many chunks of a split request are sent at once by default
+const DefaultChunkConcurrency = 4
+
+// Helper function to get how many chunks may be in flight at once
+func (client *Client) chunkConcurrency() int {
+	if client.ChunkConcurrency > 0 {
+		return client.ChunkConcurrency
+	}
+	return DefaultChunkConcurrency
+}
+
+// Helper function to get the limits requests are split by without calling /info/.
+// They are the cached /info/ limits once any Info call has succeeded, the fallback until then.
+func (client *Client) requestLimits() Limits {
+	if limits, ok := client.cachedLimits(); ok {
+		return limits
+	}
+	return client.FallbackLimits
+}
+
+// Helper function to split a request's inputs into spans that fit the batch limits,
+// or the BatchSizer's current size when it is set
+func (client *Client) requestSpans(urls []string, data [][]byte) ([]span, error) {
+	limits := client.requestLimits()
+	size := client.batchSize(limits.MaxBatchSize)
+
+	if len(data) > 0 {
+		return chunkImages(data, size, limits.MaxImageBytes)
+	}
+
+	if size <= 0 {
+		return []span{{0, len(urls)}}, nil
+	}
+
+	var spans []span
+	for start := 0; start < len(urls); start += size {
+		end := start + size
+		if end > len(urls) {
+			end = len(urls)
+		}
+		spans = append(spans, span{start, end})
+	}
+	return spans, nil
+}
+
+// Helper function to get the part of a request's local ids for a span, if they are aligned with its inputs
+func spanLocalIDs(localIDs []string, inputs int, s span) []string {
+	if len(localIDs) != inputs {
+		return localIDs
+	}
+	return localIDs[s.start:s.end]
+}
+
+// Helper function to send the spans of an oversized tag request, a few at a time, and merge the results in input order
+func (client *Client) tagChunked(ctx context.Context, req TagRequest, spans []span, opts *requestOptions) (*TagResp, []byte, error) {
+	inputs := len(req.URLs) + len(req.EncodedData)
+	requests := make([]TagRequest, len(spans))
+	responses := make([]*TagResp, len(spans))
+	errs := make([]error, len(spans))
+	sem := make(chan struct{}, client.chunkConcurrency())
+	var wg sync.WaitGroup
+
+	for c, s := range spans {
+		sub := req
+		if len(req.EncodedData) > 0 {
+			sub.EncodedData = req.EncodedData[s.start:s.end]
+		} else {
+			sub.URLs = req.URLs[s.start:s.end]
+		}
+		sub.LocalIDs = spanLocalIDs(req.LocalIDs, inputs, s)
+		requests[c] = sub
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(c int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			opts.progress.track(c, len(spans), spans[c].end-spans[c].start, func() error {
+				responses[c], _, errs[c] = client.tagOnce(ctx, requests[c], opts.withKeySuffix("-"+strconv.Itoa(c)))
+				return errs[c]
+			})
+		}(c)
+	}
+
+	wg.Wait()
+
+	merged := &TagResp{StatusCode: StatusOK, Language: req.Language, requestedModel: req.Model, Results: make([]TagResult, inputs)}
+	var failed []ChunkFailure
+	metaSet := false
+
+	// Each chunk fills only its own span, so results never move between chunks
+	for c, resp := range responses {
+		s := spans[c]
+		if errs[c] != nil {
+			failed = append(failed, ChunkFailure{Start: s.start, End: s.end, URLs: requests[c].URLs, Err: errs[c]})
+			for i := s.start; i < s.end; i++ {
+				placeholder := TagResult{StatusCode: StatusClientError, StatusMessage: errs[c].Error(), Model: req.Model}
+				if i < len(req.URLs) {
+					placeholder.URL = req.URLs[i]
+				}
+				if len(req.LocalIDs) == inputs {
+					placeholder.LocalID = req.LocalIDs[i]
+				}
+				merged.Results[i] = placeholder
+			}
+			continue
+		}
+		if !metaSet {
+			merged.Meta = resp.Meta
+			merged.Language = resp.Language
+			merged.LanguageFallback = resp.LanguageFallback
+			metaSet = true
+		}
+		copy(merged.Results[s.start:s.end], OrderByInput(requests[c], resp).Results)
+	}
+
+	for _, result := range merged.Results {
+		if result.StatusCode.IsError() {
+			merged.StatusCode = StatusPartialError
+			break
+		}
+	}
+
+	if opts.provenance {
+		for c, s := range spans {
+			id := RequestID(requests[c])
+			for i := s.start; i < s.end; i++ {
+				merged.Results[i].Provenance = &Provenance{Chunk: c, RequestID: id}
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return nil, nil, &ChunkError{Failed: failed, TagResp: merged, Chunks: len(spans)}
+	}
+
+	raw, err := json.Marshal(merged)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return merged, raw, nil
+}
+
+// Helper function to place the results of a color chunk at the positions of its inputs.
+// Color results carry no local id, so a chunk with one result per input is kept in order;
+// otherwise url results are matched by url, and image results, which have none, are cut
+// to the span. Inputs left without a result keep only their url, and false is returned.
+func placeColorResults(span []ColorImage, urls []string, results []ColorImage) bool {
+	if len(results) == len(span) || len(urls) == 0 {
+		copy(span, results)
+		return len(results) == len(span)
+	}
+
+	used := make([]bool, len(results))
+	complete := true
+	for i, u := range urls {
+		span[i].URL = u
+		found := false
+		for j, result := range results {
+			if !used[j] && result.URL == u {
+				span[i], used[j], found = result, true, true
+				break
+			}
+		}
+		complete = complete && found
+	}
+	return complete
+}
+
+// Helper function to send the spans of an oversized color request, a few at a time, and merge the results in input order
+func (client *Client) colorChunked(ctx context.Context, req ColorRequest, spans []span, opts *requestOptions) (*ColorResp, []byte, error) {
+	inputs := len(req.URLs) + len(req.EncodedData)
+	requests := make([]ColorRequest, len(spans))
+	responses := make([]*ColorResp, len(spans))
+	errs := make([]error, len(spans))
+	sem := make(chan struct{}, client.chunkConcurrency())
+	var wg sync.WaitGroup
+
+	for c, s := range spans {
+		sub := req
+		if len(req.EncodedData) > 0 {
+			sub.EncodedData = req.EncodedData[s.start:s.end]
+		} else {
+			sub.URLs = req.URLs[s.start:s.end]
+		}
+		sub.LocalIDs = spanLocalIDs(req.LocalIDs, inputs, s)
+		requests[c] = sub
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(c int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			opts.progress.track(c, len(spans), spans[c].end-spans[c].start, func() error {
+				responses[c], _, errs[c] = client.colorOnce(ctx, requests[c], opts.withKeySuffix("-"+strconv.Itoa(c)))
+				return errs[c]
+			})
+		}(c)
+	}
+
+	wg.Wait()
+
+	merged := &ColorResp{StatusCode: StatusOK, Results: make([]ColorImage, inputs)}
+	var failed []ChunkFailure
+	metaSet := false
+
+	// Each chunk fills only its own span, so results never move between chunks
+	for c, resp := range responses {
+		s := spans[c]
+		if errs[c] != nil {
+			failed = append(failed, ChunkFailure{Start: s.start, End: s.end, URLs: requests[c].URLs, Err: errs[c]})
+			for i := s.start; i < s.end; i++ {
+				if i < len(req.URLs) {
+					merged.Results[i].URL = req.URLs[i]
+				}
+			}
+			merged.StatusCode = StatusPartialError
+			continue
+		}
+		if !metaSet {
+			merged.Meta = resp.Meta
+			metaSet = true
+		}
+		if resp.StatusCode == StatusPartialError || !placeColorResults(merged.Results[s.start:s.end], requests[c].URLs, resp.Results) {
+			merged.StatusCode = StatusPartialError
+		}
+	}
+
+	if len(failed) > 0 {
+		return nil, nil, &ChunkError{Failed: failed, ColorResp: merged, Chunks: len(spans)}
+	}
+
+	raw, err := json.Marshal(merged)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return merged, raw, nil
+}
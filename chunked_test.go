@@ -0,0 +1,317 @@
+package clarifai
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTagChunksOversizedBatch(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 2
+
+	defer server.Close()
+
+	var calls int32
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		echoTagHandler(w, r)
+	})
+
+	req := TagRequest{}
+	for i := 0; i < 5; i++ {
+		req.URLs = append(req.URLs, "http://example.com/"+strconv.Itoa(i)+".jpg")
+		req.LocalIDs = append(req.LocalIDs, "id-"+strconv.Itoa(i))
+	}
+
+	resp, raw, err := client.TagRaw(req)
+
+	if err != nil {
+		t.Fatalf("TagRaw() should not return error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Five urls with a batch size of two should take three requests, took %d", calls)
+	}
+	if len(resp.Results) != 5 {
+		t.Fatalf("Merged response should hold every result, got %d", len(resp.Results))
+	}
+	for i, result := range resp.Results {
+		if result.URL != req.URLs[i] || result.LocalID != req.LocalIDs[i] {
+			t.Errorf("Result %d is for %s (%s), want %s (%s)", i, result.URL, result.LocalID, req.URLs[i], req.LocalIDs[i])
+		}
+	}
+
+	var decoded TagResp
+	if err := json.Unmarshal(raw, &decoded); err != nil || len(decoded.Results) != 5 {
+		t.Errorf("Raw JSON should hold the merged response: %v", err)
+	}
+}
+
+func TestTagChunkFailureKeepsSucceededResults(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 2
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		var req TagRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.URLs[0] == "c" {
+			w.WriteHeader(500)
+			return
+		}
+		resp := TagResp{StatusCode: StatusOK}
+		for _, u := range req.URLs {
+			resp.Results = append(resp.Results, TagResult{URL: u, StatusCode: StatusOK})
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	_, err := client.Tag(TagRequest{URLs: []string{"a", "b", "c", "d", "e"}})
+
+	var chunkErr *ChunkError
+	if !errors.As(err, &chunkErr) {
+		t.Fatalf("Tag() should return a *ChunkError, got %v", err)
+	}
+	if len(chunkErr.Failed) != 1 || chunkErr.Failed[0].Start != 2 || chunkErr.Failed[0].End != 4 {
+		t.Errorf("Only the second chunk should fail, got %+v", chunkErr.Failed)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != StatusClarifaiError {
+		t.Errorf("The chunk's own error should be reachable, got %v", apiErr)
+	}
+
+	results := chunkErr.TagResp.Results
+	want := []StatusCode{StatusOK, StatusOK, StatusClientError, StatusClientError, StatusOK}
+	for i, code := range want {
+		if results[i].StatusCode != code {
+			t.Errorf("Result %d should be %s, got %s", i, code, results[i].StatusCode)
+		}
+	}
+	if chunkErr.TagResp.StatusCode != StatusPartialError {
+		t.Errorf("Partial response should be PARTIAL_ERROR, got %s", chunkErr.TagResp.StatusCode)
+	}
+}
+
+func TestColorChunksOversizedBatch(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 2
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/color", func(w http.ResponseWriter, r *http.Request) {
+		var req ColorRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		resp := ColorResp{StatusCode: StatusOK}
+		for _, u := range req.URLs {
+			resp.Results = append(resp.Results, ColorImage{URL: u})
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	urls := []string{"a", "b", "c"}
+	resp, err := client.Color(ColorRequest{URLs: urls})
+
+	if err != nil {
+		t.Fatalf("Color() should not return error: %v", err)
+	}
+	for i, image := range resp.Results {
+		if image.URL != urls[i] {
+			t.Errorf("Result %d is for %s, want %s", i, image.URL, urls[i])
+		}
+	}
+}
+
+func TestColorChunkShortResults(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 2
+
+	defer server.Close()
+
+	// The first chunk comes back without its result for a
+	mux.HandleFunc("/v1/color", func(w http.ResponseWriter, r *http.Request) {
+		var req ColorRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		resp := ColorResp{StatusCode: StatusOK}
+		for _, u := range req.URLs {
+			if u != "a" {
+				resp.Results = append(resp.Results, ColorImage{URL: u, Colors: []Color{{Hex: "#" + u}}})
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	urls := []string{"a", "b", "c", "d"}
+	resp, err := client.Color(ColorRequest{URLs: urls})
+
+	if err != nil {
+		t.Fatalf("Color() should not return error: %v", err)
+	}
+	if len(resp.Results) != len(urls) || resp.StatusCode != StatusPartialError {
+		t.Fatalf("Expected a partial response with a result per url, got %s with %+v", resp.StatusCode, resp.Results)
+	}
+	for i, image := range resp.Results {
+		if image.URL != urls[i] {
+			t.Errorf("Result %d is for %s, want %s", i, image.URL, urls[i])
+		}
+		if hasColors := len(image.Colors) > 0; hasColors != (urls[i] != "a") {
+			t.Errorf("Result %d should only have colors if they were returned, got %+v", i, image.Colors)
+		}
+	}
+}
+
+func TestInfoCachesBatchLimit(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status_code": "OK", "results": {"max_batch_size": 3}}`))
+	})
+
+	if client.requestLimits().MaxBatchSize != DefaultMaxBatchSize {
+		t.Error("Requests should be split by the default batch size before /info/ is fetched")
+	}
+
+	if _, err := client.Info(); err != nil {
+		t.Fatalf("Info() should not return error: %v", err)
+	}
+
+	if client.requestLimits().MaxBatchSize != 3 {
+		t.Errorf("Info() should cache the batch size, got %d", client.requestLimits().MaxBatchSize)
+	}
+}
+
+func TestTagChunkFailureEncodedData(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 2
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseMultipartForm(1 << 20)
+		files := r.MultipartForm.File["encoded_data"]
+		f, _ := files[0].Open()
+		data, _ := ioutil.ReadAll(f)
+		if string(data) == base64.StdEncoding.EncodeToString([]byte("a")) {
+			w.WriteHeader(500)
+			return
+		}
+
+		// Results carry no url or local id, only the class naming the image
+		resp := TagResp{StatusCode: StatusOK}
+		for _, header := range files {
+			f, _ := header.Open()
+			encoded, _ := ioutil.ReadAll(f)
+			decoded, _ := base64.StdEncoding.DecodeString(string(encoded))
+			result := TagResult{StatusCode: StatusOK}
+			result.Result.Tag.Classes = []string{string(decoded)}
+			resp.Results = append(resp.Results, result)
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	_, err := client.Tag(TagRequest{EncodedData: [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d")}})
+
+	var chunkErr *ChunkError
+	if !errors.As(err, &chunkErr) {
+		t.Fatalf("Tag() should return a *ChunkError, got %v", err)
+	}
+
+	results := chunkErr.TagResp.Results
+	for i := 0; i < 2; i++ {
+		if results[i].StatusCode != StatusClientError || len(results[i].Result.Tag.Classes) != 0 {
+			t.Errorf("Result %d belongs to the failed chunk and should be a placeholder, got %+v", i, results[i])
+		}
+	}
+	for i, image := range []string{"c", "d"} {
+		result := results[i+2]
+		if result.StatusCode != StatusOK || len(result.Result.Tag.Classes) != 1 || result.Result.Tag.Classes[0] != image {
+			t.Errorf("Result %d should be the tags of image %s, got %+v", i+2, image, result)
+		}
+	}
+}
+
+func TestTagChunkConcurrency(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 1
+	client.ChunkConcurrency = 2
+
+	defer server.Close()
+
+	var inFlight, peak int32
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		echoTagHandler(w, r)
+	})
+
+	if _, err := client.Tag(TagRequest{URLs: []string{"a", "b", "c", "d", "e", "f"}}); err != nil {
+		t.Fatalf("Tag() should not return error: %v", err)
+	}
+
+	if peak > 2 {
+		t.Errorf("At most ChunkConcurrency chunks should be in flight, saw %d", peak)
+	}
+}
+
+func TestTagChunkProvenance(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 2
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", echoTagHandler)
+
+	resp, err := client.Tag(TagRequest{URLs: []string{"a", "b", "c"}}, WithProvenance())
+
+	if err != nil {
+		t.Fatalf("Tag() should not return error: %v", err)
+	}
+
+	want := []int{0, 0, 1}
+	for i, result := range resp.Results {
+		if result.Provenance == nil || result.Provenance.Chunk != want[i] {
+			t.Errorf("Result %d should come from chunk %d, got %+v", i, want[i], result.Provenance)
+		}
+	}
+}
@@ -3,13 +3,16 @@ package clarifai
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Configurations
@@ -18,15 +21,65 @@ const (
 	rootURL = "https://api.clarifai.com"
 )
 
-// Client contains scoped variables forindividual clients
+// DefaultDialTimeout bounds how long establishing a connection may take,
+// separately from the overall request Timeout
+const DefaultDialTimeout = 10 * time.Second
+
+// Client contains scoped variables forindividual clients.
+// A Client is safe for concurrent use by multiple goroutines. Its exported fields are
+// configuration: set them before sharing the client. AccessToken and Throttled are kept
+// up to date by the client; read them with CurrentAccessToken and IsThrottled instead.
 type Client struct {
 	ClientID     string
 	ClientSecret string
 	AccessToken  string
 	APIRoot      string
 	Throttled    bool
+	Timeout      time.Duration
+	HTTPClient   *http.Client
+
+	// MaxDecompressedBytes caps the size of a decoded response body; zero means no limit
+	MaxDecompressedBytes int64
+	// SendEmptySlices sends empty local_ids as [] rather than omitting them
+	SendEmptySlices bool
+
+	// Logger receives structured entries, such as failed results in a batch
+	Logger Logger
+	// FailureLogLevel is the level failed results are logged at
+	FailureLogLevel LogLevel
+	// FallbackLimits are used in place of the /info/ limits when it is unavailable
+	FallbackLimits Limits
+	// ChunkConcurrency caps how many chunks of a split request are sent at once;
+	// zero means DefaultChunkConcurrency
+	ChunkConcurrency int
+	// AdaptiveThrottling slows requests as the rate limit headers report the remaining calls running out
+	AdaptiveThrottling bool
+	// BatchSizer, when set, splits requests by a batch size adapted to the observed latency
+	// instead of MaxBatchSize; nil keeps the fixed size
+	BatchSizer *AdaptiveBatchSizer
+
+	tokenSource TokenSource
+	uploadField string
+
+	// mu guards AccessToken, Throttled and the fields below
+	mu           sync.Mutex
+	limits       *Limits
+	processors   []ResultProcessor
+	rateLimit    RateLimit
+	rateLimitAt  time.Time
+	nextSend     time.Time
+	serverTiming ServerTiming
+
+	// refreshMu makes concurrent callers holding the same rejected token refresh it once
+	refreshMu sync.Mutex
 }
 
+type contextKey string
+
+// TimeoutContextKey is the context key holding a time.Duration timeout for a single request.
+// When absent, the client's Timeout is used; a zero timeout means no limit.
+const TimeoutContextKey = contextKey("clarifai.timeout")
+
 // TokenResp is the expected response from /token/
 type TokenResp struct {
 	AccessToken string `json:"access_token"`
@@ -36,8 +89,23 @@ type TokenResp struct {
 }
 
 // NewClient initializes a new Clarifai client
-func NewClient(clientID, clientSecret string) *Client {
-	return &Client{clientID, clientSecret, "unasigned", rootURL, false}
+func NewClient(clientID, clientSecret string, opts ...ClientOption) *Client {
+	client := &Client{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AccessToken:  "unasigned",
+		APIRoot:      rootURL,
+		HTTPClient:   newHTTPClient(DefaultDialTimeout),
+
+		FailureLogLevel: LogWarn,
+		FallbackLimits:  DefaultLimits(),
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client
 }
 
 func (client *Client) requestAccessToken() error {
@@ -53,12 +121,11 @@ func (client *Client) requestAccessToken() error {
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+client.AccessToken)
+	req.Header.Set("Authorization", "Bearer "+client.CurrentAccessToken())
 	req.Header.Set("Content-Length", strconv.Itoa(len(form.Encode())))
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	httpClient := &http.Client{}
-	res, err := httpClient.Do(req)
+	res, err := client.httpClient().Do(req)
 
 	if err != nil {
 		return err
@@ -83,61 +150,176 @@ func (client *Client) requestAccessToken() error {
 	return nil
 }
 
-func (client *Client) commonHTTPRequest(jsonBody interface{}, endpoint, verb string, retry bool) ([]byte, error) {
-	if jsonBody == nil {
-		jsonBody = struct{}{}
+func (client *Client) commonHTTPRequest(ctx context.Context, jsonBody interface{}, endpoint, verb string, retry bool, opts *requestOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &requestOptions{}
 	}
 
-	body, err := json.Marshal(jsonBody)
+	token := opts.accessToken
+	if token == "" {
+		var err error
+		if token, err = client.currentToken(); err != nil {
+			return nil, err
+		}
+	}
 
-	if err != nil {
-		return nil, err
+	if client.AdaptiveThrottling || opts.throttle {
+		if err := client.waitForRateLimit(ctx); err != nil {
+			return nil, err
+		}
 	}
 
-	req, err := http.NewRequest(verb, client.buildURL(endpoint), bytes.NewReader(body))
+	req, err := client.newRequest(verb, endpoint, jsonBody, token)
 
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Content-Length", strconv.Itoa(len(body)))
-	req.Header.Set("Authorization", "Bearer "+client.AccessToken)
-	req.Header.Set("Content-Type", "application/json")
+	if opts.idempotencyKey != "" && verb == "POST" {
+		req.Header.Set("Idempotency-Key", opts.idempotencyKey)
+	}
+
+	timeout := client.Timeout
+	if d, ok := ctx.Value(TimeoutContextKey).(time.Duration); ok {
+		timeout = d
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
-	httpClient := &http.Client{}
-	res, err := httpClient.Do(req)
+	start := time.Now()
+	res, err := client.httpClient().Do(req.WithContext(ctx))
 
 	if err != nil {
+		// Report cancellation as the context's own error rather than a wrapped transport error
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		return nil, err
 	}
 
+	defer res.Body.Close()
+
+	client.recordResponse(res.Header, time.Since(start))
+
 	switch res.StatusCode {
 	case 200, 201:
-		if client.Throttled {
-			client.setThrottle(false)
+		client.setThrottle(false)
+		body, err := client.readBody(res)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+		if err := statusError(res.StatusCode, body); err != nil {
+			return nil, err
 		}
-		defer res.Body.Close()
-		body, err := ioutil.ReadAll(res.Body)
-		return body, err
+		return body, nil
 	case 401:
-		if !retry {
-			err := client.requestAccessToken()
+		// An overridden token belongs to the caller, so the shared token is never refreshed for it
+		if !retry && !opts.noRetry && opts.accessToken == "" {
+			err := client.refreshToken(token)
 			if err != nil {
 				return nil, err
 			}
-			return client.commonHTTPRequest(jsonBody, endpoint, verb, true)
+			return client.commonHTTPRequest(ctx, jsonBody, endpoint, verb, true, opts)
 		}
-		return nil, errors.New("TOKEN_INVALID")
+		return nil, newAPIError(res, StatusTokenInvalid)
 	case 429:
 		client.setThrottle(true)
-		return nil, errors.New("THROTTLED")
+		return nil, newAPIError(res, StatusThrottled)
 	case 400:
-		return nil, errors.New("ALL_ERROR")
+		return nil, newAPIError(res, StatusAllError)
 	case 500:
-		return nil, errors.New("CLARIFAI_ERROR")
+		return nil, newAPIError(res, StatusClarifaiError)
 	default:
-		return nil, errors.New("UNEXPECTED_STATUS_CODE")
+		return nil, newAPIError(res, StatusUnexpected)
+	}
+}
+
+// BuildRequest returns the authenticated request the client would send for the
+// given verb, endpoint (e.g. "tag") and JSON body, for callers to inspect or send themselves
+func (client *Client) BuildRequest(verb, endpoint string, jsonBody interface{}) (*http.Request, error) {
+	token, err := client.currentToken()
+
+	if err != nil {
+		return nil, err
 	}
+
+	return client.newRequest(verb, endpoint, jsonBody, token)
+}
+
+// Helper function to build an API request with its auth and default headers
+func (client *Client) newRequest(verb, endpoint string, jsonBody interface{}, token string) (*http.Request, error) {
+	if jsonBody == nil {
+		jsonBody = struct{}{}
+	}
+
+	var body []byte
+	var err error
+	contentType := "application/json"
+
+	if form, ok := jsonBody.(multipartBody); ok {
+		body, contentType, err = form.encode(client.uploadFieldName())
+	} else {
+		body, err = json.Marshal(jsonBody)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(verb, client.buildURL(endpoint), bytes.NewReader(body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", contentType)
+
+	return req, nil
+}
+
+// SetDialTimeout replaces the client's transport with one that gives up connecting after d.
+// Any other customizations of the injected http.Client's transport are discarded.
+func (client *Client) SetDialTimeout(d time.Duration) {
+	httpClient := newHTTPClient(d)
+	if client.HTTPClient != nil {
+		httpClient.Timeout = client.HTTPClient.Timeout
+	}
+	client.HTTPClient = httpClient
+}
+
+// dialContext opens the connections of the transports built by newHTTPClient
+var dialContext = (&net.Dialer{KeepAlive: 30 * time.Second}).DialContext
+
+// Helper function to build an http.Client whose connections fail fast after dialTimeout
+func newHTTPClient(dialTimeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		if dialTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, dialTimeout)
+			defer cancel()
+		}
+		return dialContext(ctx, network, address)
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+// Helper function to get the injected http.Client, falling back to the default client
+func (client *Client) httpClient() *http.Client {
+	if client.HTTPClient != nil {
+		return client.HTTPClient
+	}
+	return http.DefaultClient
 }
 
 // Helper function to build URLs
@@ -148,13 +330,46 @@ func (client *Client) buildURL(endpoint string) string {
 
 // SetAccessToken will set accessToken to a new value
 func (client *Client) setAccessToken(token string) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
 	client.AccessToken = token
 }
 
+// CurrentAccessToken returns the access token the client is sending
+func (client *Client) CurrentAccessToken() string {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.AccessToken
+}
+
+// IsThrottled reports whether the last response was rejected as throttled
+func (client *Client) IsThrottled() bool {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.Throttled
+}
+
 func (client *Client) setAPIRoot(root string) {
 	client.APIRoot = root
 }
 
 func (client *Client) setThrottle(throttle bool) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
 	client.Throttled = throttle
 }
+
+// Helper function to remember the rate limit and timing reported by a response
+func (client *Client) recordResponse(header http.Header, elapsed time.Duration) {
+	now := time.Now()
+	rateLimit := parseRateLimit(header, now)
+	timing := ServerTiming{Metrics: parseServerTiming(header), Elapsed: elapsed}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	// A response without the headers, such as a bare 429, keeps the last window until it resets
+	if rateLimit.Known() || !windowOpen(client.rateLimit, client.rateLimitAt, now) {
+		client.rateLimit, client.rateLimitAt = rateLimit, now
+	}
+	client.serverTiming = timing
+}
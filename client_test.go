@@ -1,10 +1,16 @@
 package clarifai
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 const (
@@ -90,3 +96,233 @@ func TestAccessTokenIsSaved(t *testing.T) {
 		t.Errorf("requestAccessToken() should store the access token. Expected: 1234567890abcdefg, Got: %v", client.AccessToken)
 	}
 }
+
+func TestTimeoutContextValue(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":{}}`)
+	})
+
+	ctx := context.WithValue(context.Background(), TimeoutContextKey, 10*time.Millisecond)
+	_, err := client.commonHTTPRequest(ctx, nil, "info", "GET", false, nil)
+
+	if err == nil {
+		t.Error("commonHTTPRequest() should return an err when the context timeout elapses")
+	}
+
+	_, err = client.commonHTTPRequest(context.Background(), nil, "info", "GET", false, nil)
+
+	if err != nil {
+		t.Errorf("commonHTTPRequest() should not time out without a timeout set: %v", err)
+	}
+}
+
+func TestClientTimeoutDefault(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.Timeout = 10 * time.Millisecond
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(200)
+	})
+
+	_, err := client.Info()
+
+	if err == nil {
+		t.Error("Info() should return an err when the client timeout elapses")
+	}
+}
+
+func TestBuildRequest(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+	client.setAccessToken("1234567890abcdefg")
+
+	req, err := client.BuildRequest("POST", "tag", TagRequest{URLs: []string{"http://www.clarifai.com/img/metro-north.jpg"}})
+
+	if err != nil {
+		t.Fatalf("BuildRequest() should not return an err: %v", err)
+	}
+
+	if req.URL.String() != "https://api.clarifai.com/v1/tag" || req.Method != "POST" {
+		t.Errorf("BuildRequest() should target the endpoint. Got: %v %v", req.Method, req.URL)
+	}
+
+	if req.Header.Get("Authorization") != "Bearer 1234567890abcdefg" || req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("BuildRequest() should set auth and default headers. Got: %v", req.Header)
+	}
+}
+
+type staticTokenSource struct {
+	token string
+	err   error
+}
+
+func (ts staticTokenSource) Token() (string, error) {
+	return ts.token, ts.err
+}
+
+func (ts staticTokenSource) Refresh(stale string) (string, error) {
+	return ts.token, ts.err
+}
+
+func TestBuildRequestTokenSource(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret, WithTokenSource(staticTokenSource{token: "from-source"}))
+
+	req, err := client.BuildRequest("GET", "info", nil)
+
+	if err != nil {
+		t.Fatalf("BuildRequest() should not return an err: %v", err)
+	}
+	if req.Header.Get("Authorization") != "Bearer from-source" {
+		t.Errorf("BuildRequest() should use the token source. Got: %v", req.Header.Get("Authorization"))
+	}
+
+	client = NewClient(ClientID, ClientSecret, WithTokenSource(staticTokenSource{err: errors.New("No token")}))
+
+	if _, err := client.BuildRequest("GET", "info", nil); err == nil {
+		t.Error("BuildRequest() should return the token source error")
+	}
+}
+
+func TestSetDialTimeout(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+	client.HTTPClient.Timeout = time.Minute
+
+	client.SetDialTimeout(time.Millisecond)
+
+	if client.HTTPClient.Timeout != time.Minute {
+		t.Errorf("SetDialTimeout() should keep the overall timeout. Got: %v", client.HTTPClient.Timeout)
+	}
+
+	if _, ok := client.HTTPClient.Transport.(*http.Transport); !ok {
+		t.Error("SetDialTimeout() should install a transport")
+	}
+}
+
+func TestDialTimeoutFailsFast(t *testing.T) {
+	// A host that never answers the connection attempt
+	defer func(dial func(context.Context, string, string) (net.Conn, error)) { dialContext = dial }(dialContext)
+	dialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot("http://unreachable.invalid")
+	client.SetDialTimeout(50 * time.Millisecond)
+
+	start := time.Now()
+	_, err := client.Info()
+
+	if err == nil {
+		t.Error("Info() should return an err when the host is unreachable")
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Info() should give up after the dial timeout. Took: %v", elapsed)
+	}
+}
+
+func TestClientConcurrentUse(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	var tokenRequests int32
+	mux.HandleFunc("/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenRequests, 1)
+		fmt.Fprintln(w, `{"access_token":"fresh","expires_in":36000,"scope": "api_access", "token_type": "Bearer"}`)
+	})
+
+	// Every call starts with the stale token, so the refresh is raced too
+	authorized := func(w http.ResponseWriter, r *http.Request) bool {
+		w.Header().Set("X-RateLimit-Remaining", "100")
+		w.Header().Set("Server-Timing", "app;dur=1")
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(401)
+			return false
+		}
+		return true
+	}
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		if authorized(w, r) {
+			echoTagHandler(w, r)
+		}
+	})
+	mux.HandleFunc("/v1/color", func(w http.ResponseWriter, r *http.Request) {
+		if authorized(w, r) {
+			fmt.Fprintln(w, `{"status_code":"OK","results":[{}]}`)
+		}
+	})
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		if authorized(w, r) {
+			fmt.Fprintln(w, `{"status_code":"OK","results":{"max_batch_size":64}}`)
+		}
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+
+	for i := 0; i < 20; i++ {
+		wg.Add(5)
+		go func() {
+			defer wg.Done()
+			_, err := client.Tag(TagRequest{URLs: []string{"http://example.com/a.jpg"}})
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := client.Color(ColorRequest{URLs: []string{"http://example.com/a.jpg"}})
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := client.Info()
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			client.Limits()
+			client.LastRateLimit()
+			client.LastServerTiming()
+			client.ThrottleState()
+			client.IsThrottled()
+			errs <- nil
+		}()
+		go func() {
+			defer wg.Done()
+			client.AddResultProcessor(ProbSorter{})
+			errs <- nil
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Concurrent calls should not fail: %v", err)
+		}
+	}
+
+	if n := atomic.LoadInt32(&tokenRequests); n != 1 {
+		t.Errorf("The stale token should be refreshed once, got %d refreshes", n)
+	}
+}
@@ -0,0 +1,248 @@
+package clarifai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// colorJSON has the fields of Color without its UnmarshalJSON method
+type colorJSON Color
+
+// UnmarshalJSON decodes a color in the current shape, with hex and w3c at the top level,
+// or in the legacy shape that nests them under "color":
+//
+//	{"color": {"hex": "#2f4f4f", "w3c": {"hex": "#2f4f4f", "name": "DarkSlateGray"}}, "density": 0.5}
+func (c *Color) UnmarshalJSON(data []byte) error {
+	legacy := struct {
+		Color   *colorJSON `json:"color"`
+		Density float64    `json:"density"`
+	}{}
+
+	if err := json.Unmarshal(data, &legacy); err == nil && legacy.Color != nil {
+		*c = Color(*legacy.Color)
+		c.Density = legacy.Density
+		return nil
+	}
+
+	return json.Unmarshal(data, (*colorJSON)(c))
+}
+
+// RGBA parses the color's hex value, e.g. "#2f4f4f"
+func (c Color) RGBA() (color.RGBA, error) {
+	return parseHexColor(c.Hex)
+}
+
+// Helper function to parse a "#rrggbb" or "#rgb" string into an opaque color
+func parseHexColor(hex string) (color.RGBA, error) {
+	s := strings.TrimPrefix(hex, "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+
+	if len(s) != 6 {
+		return color.RGBA{}, fmt.Errorf("Invalid hex color: %q", hex)
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("Invalid hex color: %q", hex)
+	}
+
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// WritePalette renders the image's colors as a width x height PNG strip,
+// giving each color a slice of the width proportional to its density
+func (img ColorImage) WritePalette(w io.Writer, width, height int) error {
+	if width < 1 || height < 1 {
+		return errors.New("Palette dimensions must be positive")
+	}
+
+	var total float64
+	for _, c := range img.Colors {
+		total += c.Density
+	}
+
+	if total <= 0 {
+		return errors.New("Image has no colors to render")
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	var cumulative float64
+	start := 0
+
+	for _, c := range img.Colors {
+		fill, err := c.RGBA()
+
+		if err != nil {
+			return err
+		}
+
+		cumulative += c.Density
+		end := int(cumulative/total*float64(width) + 0.5)
+
+		for x := start; x < end && x < width; x++ {
+			for y := 0; y < height; y++ {
+				canvas.SetRGBA(x, y, fill)
+			}
+		}
+		start = end
+	}
+
+	return png.Encode(w, canvas)
+}
+
+// Coverage returns the cumulative density explained by the top 1, 2, ... K colors,
+// taking the colors from most to least dense. The last value is the total density.
+func (img ColorImage) Coverage() []float64 {
+	densities := make([]float64, len(img.Colors))
+	for i, c := range img.Colors {
+		densities[i] = c.Density
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(densities)))
+
+	var cumulative float64
+	for i, density := range densities {
+		cumulative += density
+		densities[i] = cumulative
+	}
+
+	return densities
+}
+
+// ColorSummary is a compact description of an image's colors
+type ColorSummary struct {
+	// Average is the density-weighted mean of the colors
+	Average color.RGBA
+	// Hex is Average as a "#rrggbb" string
+	Hex string
+	// Warmth ranges from -1 (all blue) to 1 (all red), from the red and blue balance of Average
+	Warmth float64
+	// Temperature is "warm", "cool" or "neutral", from Warmth
+	Temperature string
+	// Dominant is the W3C name of the densest color
+	Dominant string
+}
+
+// Warmth beyond which a summary counts as warm or cool
+const neutralWarmth = 0.1
+
+// Summary describes the image's colors by their average, warmth and dominant name
+func (img ColorImage) Summary() (ColorSummary, error) {
+	var summary ColorSummary
+	var r, g, b, total, densest float64
+
+	for _, c := range img.Colors {
+		rgba, err := c.RGBA()
+
+		if err != nil {
+			return summary, err
+		}
+
+		r += float64(rgba.R) * c.Density
+		g += float64(rgba.G) * c.Density
+		b += float64(rgba.B) * c.Density
+		total += c.Density
+
+		if c.Density > densest || summary.Dominant == "" {
+			densest = c.Density
+			summary.Dominant = c.W3C.Name
+			if summary.Dominant == "" {
+				summary.Dominant, _ = c.NearestW3C()
+			}
+		}
+	}
+
+	if total <= 0 {
+		return summary, errors.New("Image has no colors to summarize")
+	}
+
+	summary.Average = color.RGBA{R: uint8(r/total + 0.5), G: uint8(g/total + 0.5), B: uint8(b/total + 0.5), A: 0xff}
+	summary.Hex = fmt.Sprintf("#%02x%02x%02x", summary.Average.R, summary.Average.G, summary.Average.B)
+	summary.Warmth = (float64(summary.Average.R) - float64(summary.Average.B)) / 255
+
+	switch {
+	case summary.Warmth > neutralWarmth:
+		summary.Temperature = "warm"
+	case summary.Warmth < -neutralWarmth:
+		summary.Temperature = "cool"
+	default:
+		summary.Temperature = "neutral"
+	}
+
+	return summary, nil
+}
+
+// Aligned lays out every image's colors against a shared reference palette, so that
+// column N of each row holds the same hue family across the batch. Each reference
+// entry is a W3C color name (e.g. "Navy") or a hex value.
+//
+// Every color of an image is assigned to the reference entry nearest to it by
+// Euclidean distance in RGB, ties going to the earlier entry. When several colors
+// of an image land on the same entry the densest one is kept; entries no color
+// landed on hold the zero Color, with an empty Hex and no density.
+func (resp *ColorResp) Aligned(reference []string) ([][]Color, error) {
+	if len(reference) < 1 {
+		return nil, errors.New("Requires at least one reference color")
+	}
+
+	palette := make([]color.RGBA, len(reference))
+	for i, ref := range reference {
+		hex := ref
+		if !strings.HasPrefix(ref, "#") {
+			named, ok := W3CColors[ref]
+			if !ok {
+				return nil, fmt.Errorf("Unknown W3C color: %q", ref)
+			}
+			hex = named
+		}
+
+		c, err := parseHexColor(hex)
+		if err != nil {
+			return nil, err
+		}
+		palette[i] = c
+	}
+
+	rows := make([][]Color, len(resp.Results))
+	for r, img := range resp.Results {
+		row := make([]Color, len(reference))
+		for _, c := range img.Colors {
+			rgba, err := c.RGBA()
+			if err != nil {
+				return nil, err
+			}
+
+			slot := nearestColor(palette, rgba)
+			if row[slot].Hex == "" || c.Density > row[slot].Density {
+				row[slot] = c
+			}
+		}
+		rows[r] = row
+	}
+
+	return rows, nil
+}
+
+// Helper function to find the index of the palette color closest to c
+func nearestColor(palette []color.RGBA, c color.RGBA) int {
+	best, bestDist := 0, -1.0
+	for i, p := range palette {
+		dr := float64(p.R) - float64(c.R)
+		dg := float64(p.G) - float64(c.G)
+		db := float64(p.B) - float64(c.B)
+		if dist := dr*dr + dg*dg + db*db; bestDist < 0 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
@@ -0,0 +1,135 @@
+package clarifai
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestColorRGBA(t *testing.T) {
+	c, err := Color{Hex: "#2f4f4f"}.RGBA()
+
+	if err != nil || c != (color.RGBA{0x2f, 0x4f, 0x4f, 0xff}) {
+		t.Errorf("RGBA() should parse the hex value. Got: %v, %v", c, err)
+	}
+
+	if _, err := (Color{Hex: "nope"}).RGBA(); err == nil {
+		t.Error("RGBA() should return an err for an invalid hex value")
+	}
+}
+
+func TestWritePalette(t *testing.T) {
+	img := ColorImage{Colors: []Color{{Hex: "#ff0000", Density: 0.75}, {Hex: "#0000ff", Density: 0.25}}}
+	buf := new(bytes.Buffer)
+
+	err := img.WritePalette(buf, 8, 2)
+
+	if err != nil {
+		t.Fatalf("WritePalette() should not return an err: %v", err)
+	}
+
+	decoded, err := png.Decode(buf)
+
+	if err != nil {
+		t.Fatalf("WritePalette() should write a valid PNG: %v", err)
+	}
+
+	if r, _, _, _ := decoded.At(5, 1).RGBA(); r != 0xffff {
+		t.Error("WritePalette() should give the first color 75% of the width")
+	}
+
+	if _, _, b, _ := decoded.At(6, 0).RGBA(); b != 0xffff {
+		t.Error("WritePalette() should give the second color the remaining width")
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	img := ColorImage{Colors: []Color{{Density: 0.25}, {Density: 0.5}, {Density: 0.125}}}
+
+	coverage := img.Coverage()
+
+	if len(coverage) != 3 || coverage[0] != 0.5 || coverage[1] != 0.75 || coverage[2] != 0.875 {
+		t.Errorf("Coverage() should accumulate densities from the densest color. Got: %v", coverage)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	red := Color{Hex: "#ff0000", Density: 0.75}
+	red.W3C.Name = "Red"
+	img := ColorImage{Colors: []Color{red, {Hex: "#0000ff", Density: 0.25}}}
+
+	summary, err := img.Summary()
+
+	if err != nil {
+		t.Fatalf("Summary() should not return an err: %v", err)
+	}
+
+	if summary.Hex != "#bf0040" || summary.Dominant != "Red" || summary.Temperature != "warm" {
+		t.Errorf("Summary() should weight colors by density. Got: %+v", summary)
+	}
+}
+
+func TestSummaryCool(t *testing.T) {
+	img := ColorImage{Colors: []Color{{Hex: "#0000ff", Density: 1}}}
+
+	summary, err := img.Summary()
+
+	if err != nil || summary.Temperature != "cool" || summary.Dominant != "Blue" {
+		t.Errorf("Summary() should fall back to the nearest W3C name. Got: %+v, %v", summary, err)
+	}
+}
+
+func TestAligned(t *testing.T) {
+	resp := &ColorResp{Results: []ColorImage{
+		{Colors: []Color{{Hex: "#0000f0", Density: 0.6}, {Hex: "#f00000", Density: 0.4}}},
+		{Colors: []Color{{Hex: "#ee1111", Density: 0.3}, {Hex: "#ff0000", Density: 0.5}, {Hex: "#00ff00", Density: 0.2}}},
+	}}
+
+	rows, err := resp.Aligned([]string{"Red", "Blue", "#00ff00"})
+
+	if err != nil {
+		t.Fatalf("Aligned() should not return error: %v", err)
+	}
+
+	expected := [][]string{{"#f00000", "#0000f0", ""}, {"#ff0000", "", "#00ff00"}}
+	for r, row := range rows {
+		for slot, c := range row {
+			if c.Hex != expected[r][slot] {
+				t.Errorf("Image %d slot %d should hold %q, got %q", r, slot, expected[r][slot], c.Hex)
+			}
+		}
+	}
+}
+
+func TestAlignedUnknownReference(t *testing.T) {
+	resp := &ColorResp{}
+
+	if _, err := resp.Aligned([]string{"NotAColor"}); err == nil {
+		t.Error("Aligned() should reject unknown color names")
+	}
+}
+
+func TestColorUnmarshalCurrentShape(t *testing.T) {
+	var c Color
+	err := json.Unmarshal([]byte(`{"w3c": {"hex": "#2f4f4f", "name": "DarkSlateGray"}, "hex": "#2a4e50", "density": 0.5}`), &c)
+
+	if err != nil || c.Hex != "#2a4e50" || c.W3C.Name != "DarkSlateGray" || c.Density != 0.5 {
+		t.Errorf("Unexpected color from the current shape: %+v, %v", c, err)
+	}
+}
+
+func TestColorUnmarshalLegacyShape(t *testing.T) {
+	var resp ColorResp
+	err := json.Unmarshal([]byte(`{"status_code": "OK", "results": [{"colors": [{"color": {"hex": "#2a4e50", "w3c": {"hex": "#2f4f4f", "name": "DarkSlateGray"}}, "density": 0.25}]}]}`), &resp)
+
+	if err != nil {
+		t.Fatalf("Unmarshal should not return error: %v", err)
+	}
+
+	c := resp.Results[0].Colors[0]
+	if c.Hex != "#2a4e50" || c.W3C.Hex != "#2f4f4f" || c.W3C.Name != "DarkSlateGray" || c.Density != 0.25 {
+		t.Errorf("Unexpected color from the legacy shape: %+v", c)
+	}
+}
@@ -0,0 +1,93 @@
+package clarifai
+
+import "fmt"
+
+// ConceptMapping is a one-to-one mapping between Clarifai catids and an application's
+// own concept ids. Added to a client with WithConceptMapping, it rewrites the CatIDs of
+// every tag response; TagResult.RawCatIDs still returns the catids Clarifai sent.
+type ConceptMapping struct {
+	toInternal map[string]string
+	toCatID    map[string]string
+}
+
+// NewConceptMapping builds a mapping from catids to internal ids.
+// Two catids mapping to the same internal id are rejected, as they could not be told apart.
+func NewConceptMapping(catIDToInternal map[string]string) (*ConceptMapping, error) {
+	m := &ConceptMapping{
+		toInternal: make(map[string]string, len(catIDToInternal)),
+		toCatID:    make(map[string]string, len(catIDToInternal)),
+	}
+
+	for catID, internal := range catIDToInternal {
+		if other, ok := m.toCatID[internal]; ok {
+			return nil, fmt.Errorf("Catids %q and %q both map to %q", other, catID, internal)
+		}
+		m.toInternal[catID] = internal
+		m.toCatID[internal] = catID
+	}
+
+	return m, nil
+}
+
+// WithConceptMapping makes the client rewrite the catids of tag responses with m.
+// A nil mapping is ignored.
+func WithConceptMapping(m *ConceptMapping) ClientOption {
+	return func(client *Client) {
+		if m != nil {
+			client.AddResultProcessor(m)
+		}
+	}
+}
+
+// Internal returns the internal id of a catid
+func (m *ConceptMapping) Internal(catID string) (string, bool) {
+	internal, ok := m.toInternal[catID]
+	return internal, ok
+}
+
+// CatID returns the catid of an internal id
+func (m *ConceptMapping) CatID(internal string) (string, bool) {
+	catID, ok := m.toCatID[internal]
+	return catID, ok
+}
+
+// Invert returns the mapping from internal ids to catids
+func (m *ConceptMapping) Invert() *ConceptMapping {
+	return &ConceptMapping{toInternal: m.toCatID, toCatID: m.toInternal}
+}
+
+// Apply rewrites the catids of every result to internal ids. Catids without a mapping
+// are kept as they are. A result is rewritten at most once.
+func (m *ConceptMapping) Apply(resp *TagResp) {
+	for i := range resp.Results {
+		result := &resp.Results[i]
+		if result.rawCatIDs != nil {
+			continue
+		}
+
+		catIDs := result.Result.Tag.CatIDs
+		result.rawCatIDs = append([]string{}, catIDs...)
+		for j, catID := range catIDs {
+			if internal, ok := m.toInternal[catID]; ok {
+				catIDs[j] = internal
+			}
+		}
+	}
+}
+
+// Process applies the mapping, so a ConceptMapping can be used as a ResultProcessor
+func (m *ConceptMapping) Process(resp *TagResp) error {
+	m.Apply(resp)
+	return nil
+}
+
+// RawCatIDs returns the catids as sent by Clarifai, before any ConceptMapping was applied,
+// in the current tag order. SortByProb, Limit and FilterByProb keep them aligned; if
+// CatIDs was changed otherwise, the current CatIDs are returned.
+func (result TagResult) RawCatIDs() []string {
+	catIDs := result.Result.Tag.CatIDs
+	if result.rawCatIDs == nil || len(result.rawCatIDs) != len(catIDs) {
+		return append([]string{}, catIDs...)
+	}
+	return append([]string{}, result.rawCatIDs...)
+}
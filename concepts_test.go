@@ -0,0 +1,85 @@
+package clarifai
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestConceptMapping(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	m, err := NewConceptMapping(map[string]string{"ai_8QQwMjQR": "concept:dog", "ai_FG7a8WlH": "concept:cat"})
+
+	if err != nil {
+		t.Fatalf("NewConceptMapping() should not return error: %v", err)
+	}
+
+	client := NewClient(ClientID, ClientSecret, WithConceptMapping(m))
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"status_code":"OK","results":[{"status_code":"OK","result":{"tag":{"classes":["dog","pet","cat"],"catids":["ai_8QQwMjQR","ai_unknown","ai_FG7a8WlH"],"probs":[0.5,0.9,0.1]}}}]}`)
+	})
+
+	resp, err := client.Tag(TagRequest{URLs: []string{"http://example.com/a.jpg"}})
+
+	if err != nil {
+		t.Fatalf("Tag() should not return error: %v", err)
+	}
+
+	result := resp.Results[0]
+	if !reflect.DeepEqual(result.Result.Tag.CatIDs, []string{"concept:dog", "ai_unknown", "concept:cat"}) {
+		t.Errorf("Mapped catids should be rewritten and others kept, got %v", result.Result.Tag.CatIDs)
+	}
+
+	result.SortByProb()
+	if !reflect.DeepEqual(result.RawCatIDs(), []string{"ai_unknown", "ai_8QQwMjQR", "ai_FG7a8WlH"}) {
+		t.Errorf("RawCatIDs() should follow the current order, got %v", result.RawCatIDs())
+	}
+
+	if catID, ok := m.Invert().Internal("concept:cat"); !ok || catID != "ai_FG7a8WlH" {
+		t.Errorf("Invert() should map internal ids back to catids, got %q", catID)
+	}
+}
+
+func TestRawCatIDsUnmappedCollision(t *testing.T) {
+	m, _ := NewConceptMapping(map[string]string{"a": "b"})
+	resp := &TagResp{Results: []TagResult{{}}}
+	resp.Results[0].Result.Tag.CatIDs = []string{"a", "b"}
+
+	m.Apply(resp)
+
+	if !reflect.DeepEqual(resp.Results[0].Result.Tag.CatIDs, []string{"b", "b"}) {
+		t.Errorf("Only the mapped catid should be rewritten, got %v", resp.Results[0].Result.Tag.CatIDs)
+	}
+	if !reflect.DeepEqual(resp.Results[0].RawCatIDs(), []string{"a", "b"}) {
+		t.Errorf("RawCatIDs() should return the catids as sent, got %v", resp.Results[0].RawCatIDs())
+	}
+}
+
+func TestWithConceptMappingNil(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret, WithConceptMapping(nil))
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", echoTagHandler)
+
+	if _, err := client.Tag(TagRequest{URLs: []string{"http://example.com/a.jpg"}}); err != nil {
+		t.Errorf("A nil mapping should be ignored, got %v", err)
+	}
+}
+
+func TestNewConceptMappingRejectsCollisions(t *testing.T) {
+	if _, err := NewConceptMapping(map[string]string{"a": "x", "b": "x"}); err == nil {
+		t.Error("NewConceptMapping() should reject two catids with the same internal id")
+	}
+}
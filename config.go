@@ -0,0 +1,86 @@
+package clarifai
+
+import "time"
+
+// redacted replaces credentials in a ClientConfig
+const redacted = "[REDACTED]"
+
+// ClientConfig is a snapshot of how a Client is set up, safe to log.
+// Credentials are replaced by "[REDACTED]", or left empty when unset.
+type ClientConfig struct {
+	APIRoot    string `json:"api_root"`
+	APIVersion string `json:"api_version"`
+
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	AccessToken  string `json:"access_token"`
+
+	// Timeout is the per-request timeout and HTTPTimeout the injected http.Client's own
+	Timeout     time.Duration `json:"timeout"`
+	HTTPTimeout time.Duration `json:"http_timeout"`
+
+	// RetryPolicy describes when a request is resent
+	RetryPolicy string `json:"retry_policy"`
+
+	MaxDecompressedBytes int64    `json:"max_decompressed_bytes"`
+	SendEmptySlices      bool     `json:"send_empty_slices"`
+	FailureLogLevel      LogLevel `json:"failure_log_level"`
+	ChunkConcurrency     int      `json:"chunk_concurrency"`
+	AdaptiveThrottling   bool     `json:"adaptive_throttling"`
+	UploadField          string   `json:"upload_field"`
+	FallbackLimits       Limits   `json:"fallback_limits"`
+	// Limits are the limits cached from /info/, nil until they are fetched
+	Limits *Limits `json:"limits"`
+	// AdaptiveBatchSize is the BatchSizer's current size, zero when batches are not adapted
+	AdaptiveBatchSize int `json:"adaptive_batch_size"`
+
+	SharedTokenSource bool `json:"shared_token_source"`
+	ResultProcessors  int  `json:"result_processors"`
+}
+
+// Config returns a redacted snapshot of the client's current configuration
+func (client *Client) Config() ClientConfig {
+	config := ClientConfig{
+		APIRoot:              client.APIRoot,
+		APIVersion:           version,
+		ClientID:             redact(client.ClientID),
+		ClientSecret:         redact(client.ClientSecret),
+		Timeout:              client.Timeout,
+		HTTPTimeout:          client.httpClient().Timeout,
+		RetryPolicy:          "resend once after refreshing a rejected access token",
+		MaxDecompressedBytes: client.MaxDecompressedBytes,
+		SendEmptySlices:      client.SendEmptySlices,
+		FailureLogLevel:      client.FailureLogLevel,
+		ChunkConcurrency:     client.chunkConcurrency(),
+		AdaptiveThrottling:   client.AdaptiveThrottling,
+		UploadField:          client.uploadFieldName(),
+		FallbackLimits:       client.FallbackLimits,
+		SharedTokenSource:    client.tokenSource != nil,
+	}
+
+	if limits, ok := client.cachedLimits(); ok {
+		config.Limits = &limits
+	}
+	if client.BatchSizer != nil {
+		config.AdaptiveBatchSize = client.BatchSizer.Size()
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	// The placeholder set by NewClient is not a credential
+	if client.AccessToken != "unasigned" {
+		config.AccessToken = redact(client.AccessToken)
+	}
+	config.ResultProcessors = len(client.processors)
+
+	return config
+}
+
+// Helper function to hide a credential while still showing whether it is set
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redacted
+}
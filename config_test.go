@@ -0,0 +1,47 @@
+package clarifai
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientConfig(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+	client.Timeout = 5 * time.Second
+	client.setAccessToken("secret-token")
+	client.setLimits(Limits{MaxBatchSize: 64})
+
+	config := client.Config()
+
+	if config.APIRoot != rootURL || config.APIVersion != "v1" || config.Timeout != 5*time.Second {
+		t.Errorf("Config() should capture the client settings, got %+v", config)
+	}
+	if config.Limits == nil || config.Limits.MaxBatchSize != 64 {
+		t.Errorf("Config() should include the cached limits, got %v", config.Limits)
+	}
+
+	data, err := json.Marshal(config)
+
+	if err != nil {
+		t.Fatalf("Config should marshal to JSON: %v", err)
+	}
+
+	for _, secret := range []string{ClientID, ClientSecret, "secret-token"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Config() should redact %q, got %s", secret, data)
+		}
+	}
+	if config.AccessToken != "[REDACTED]" {
+		t.Errorf("A set access token should be shown as redacted, got %q", config.AccessToken)
+	}
+}
+
+func TestClientConfigWithoutToken(t *testing.T) {
+	config := NewClient(ClientID, "").Config()
+
+	if config.AccessToken != "" || config.ClientSecret != "" || config.Limits != nil {
+		t.Errorf("Unset credentials and limits should be empty, got %+v", config)
+	}
+}
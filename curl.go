@@ -0,0 +1,88 @@
+package clarifai
+
+import (
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// curlTokenPlaceholder stands in for the access token in generated commands
+const curlTokenPlaceholder = "$CLARIFAI_ACCESS_TOKEN"
+
+// ToCurl renders the request the client would send as a cURL command, for
+// reproducing issues outside Go. req may be a TagRequest, ColorRequest,
+// FeedbackForm or an *http.Request from BuildRequest. The access token is
+// replaced by a reference to the CLARIFAI_ACCESS_TOKEN environment variable.
+// An empty string is returned if the request cannot be built.
+func (client *Client) ToCurl(req interface{}) string {
+	var httpReq *http.Request
+	var err error
+
+	switch r := req.(type) {
+	case TagRequest:
+		endpoint := "tag"
+		if len(r.Params) > 0 {
+			endpoint += "?" + r.Params.Encode()
+		}
+		httpReq, err = client.BuildRequest("POST", endpoint, client.tagBody(r))
+	case *TagRequest:
+		return client.ToCurl(*r)
+	case ColorRequest:
+		httpReq, err = client.BuildRequest("POST", "color", client.colorBody(r))
+	case *ColorRequest:
+		return client.ToCurl(*r)
+	case FeedbackForm:
+		httpReq, err = client.BuildRequest("POST", "feedback", r)
+	case *http.Request:
+		httpReq = r
+	default:
+		return ""
+	}
+
+	if err != nil || httpReq == nil {
+		return ""
+	}
+
+	parts := []string{"curl", "-X", shellQuote(httpReq.Method), shellQuote(httpReq.URL.String())}
+
+	var names []string
+	for name := range httpReq.Header {
+		// curl computes the length itself
+		if name != "Content-Length" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range httpReq.Header[name] {
+			if name == "Authorization" {
+				parts = append(parts, "-H", `"Authorization: Bearer `+curlTokenPlaceholder+`"`)
+				continue
+			}
+			parts = append(parts, "-H", shellQuote(name+": "+value))
+		}
+	}
+
+	if httpReq.GetBody != nil {
+		body, err := httpReq.GetBody()
+		if err != nil {
+			return ""
+		}
+		data, err := ioutil.ReadAll(body)
+		if err != nil {
+			return ""
+		}
+		if len(data) > 0 {
+			parts = append(parts, "--data-binary", shellQuote(string(data)))
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Helper function to quote s as a single POSIX shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
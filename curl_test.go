@@ -0,0 +1,58 @@
+package clarifai
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestToCurlTagRequest(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+	client.setAccessToken("secret-token")
+
+	cmd := client.ToCurl(TagRequest{
+		URLs:   []string{"http://example.com/it's.jpg"},
+		Params: url.Values{"select_classes": {"dog"}},
+	})
+
+	want := []string{
+		"curl -X 'POST' 'https://api.clarifai.com/v1/tag?select_classes=dog'",
+		`-H "Authorization: Bearer $CLARIFAI_ACCESS_TOKEN"`,
+		"-H 'Content-Type: application/json'",
+		`--data-binary '{"url":["http://example.com/it'\''s.jpg"]}'`,
+	}
+	for _, part := range want {
+		if !strings.Contains(cmd, part) {
+			t.Errorf("ToCurl() should contain %s, got %s", part, cmd)
+		}
+	}
+
+	if strings.Contains(cmd, "secret-token") {
+		t.Error("ToCurl() should not include the access token")
+	}
+	if strings.Contains(cmd, "Content-Length") {
+		t.Error("ToCurl() should leave Content-Length to curl")
+	}
+}
+
+func TestToCurlBuiltRequest(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+
+	req, err := client.BuildRequest("GET", "info", nil)
+
+	if err != nil {
+		t.Fatalf("BuildRequest() should not return error: %v", err)
+	}
+
+	if cmd := client.ToCurl(req); !strings.HasPrefix(cmd, "curl -X 'GET' 'https://api.clarifai.com/v1/info'") {
+		t.Errorf("Unexpected command: %s", cmd)
+	}
+}
+
+func TestToCurlUnknownRequest(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+
+	if cmd := client.ToCurl(42); cmd != "" {
+		t.Errorf("ToCurl() should return an empty string for unsupported requests, got %s", cmd)
+	}
+}
@@ -0,0 +1,104 @@
+package clarifai
+
+import "fmt"
+
+// Dedupe correlates the inputs of a request with the deduplicated request built from it
+type Dedupe struct {
+	// Positions holds, for each input of the deduplicated request, the positions
+	// of the original inputs it stands for, in order
+	Positions [][]int
+
+	localIDs []string
+	inputs   int
+	index    []int
+}
+
+// DedupeTagRequest returns req with repeated urls, or repeated encoded images, sent only
+// once, along with the Dedupe mapping them back. The first occurrence's local id is sent.
+func DedupeTagRequest(req TagRequest) (TagRequest, *Dedupe) {
+	inputs := len(req.URLs) + len(req.EncodedData)
+	d := &Dedupe{inputs: inputs, index: make([]int, inputs)}
+	aligned := len(req.LocalIDs) == inputs
+	if aligned {
+		d.localIDs = req.LocalIDs
+	}
+
+	seen := make(map[string]int)
+	var keep []int
+	for i := 0; i < inputs; i++ {
+		key := ""
+		if len(req.EncodedData) > 0 {
+			key = string(req.EncodedData[i])
+		} else {
+			key = req.URLs[i]
+		}
+
+		if j, ok := seen[key]; ok {
+			d.Positions[j] = append(d.Positions[j], i)
+			d.index[i] = j
+			continue
+		}
+
+		seen[key] = len(keep)
+		d.index[i] = len(keep)
+		d.Positions = append(d.Positions, []int{i})
+		keep = append(keep, i)
+	}
+
+	req.URLs, req.EncodedData, req.LocalIDs = keptInputs(keep, req.URLs, req.EncodedData, req.LocalIDs)
+	return req, d
+}
+
+// Expand copies each result of the deduplicated response to every original position it
+// stands for, restoring the original local ids, so results line up with the original request
+func (d *Dedupe) Expand(resp *TagResp) (*TagResp, error) {
+	if len(resp.Results) != len(d.Positions) {
+		return nil, fmt.Errorf("Response has %d results for %d deduplicated inputs", len(resp.Results), len(d.Positions))
+	}
+
+	expanded := *resp
+	expanded.Results = make([]TagResult, d.inputs)
+
+	for j, positions := range d.Positions {
+		for _, i := range positions {
+			result := resp.Results[j]
+			if d.localIDs != nil {
+				result.LocalID = d.localIDs[i]
+			}
+			expanded.Results[i] = result
+		}
+	}
+
+	return &expanded, nil
+}
+
+// FeedbackForm builds feedback correcting the inputs at the given original positions.
+// resp is the deduplicated response; every position is mapped to the docid of the
+// single result that stands for it, so correcting any copy of a repeated input, or
+// all of them, sends that docid once.
+func (d *Dedupe) FeedbackForm(resp *TagResp, positions []int, addTags, removeTags []string) (FeedbackForm, error) {
+	form := FeedbackForm{AddTags: addTags, RemoveTags: removeTags}
+
+	for _, i := range positions {
+		if i < 0 || i >= d.inputs {
+			return FeedbackForm{}, fmt.Errorf("Position %d is out of range", i)
+		}
+
+		j := d.index[i]
+		if j >= len(resp.Results) {
+			return FeedbackForm{}, fmt.Errorf("Response has no result for position %d", i)
+		}
+
+		docID := resp.Results[j].DocIDString
+		if docID == "" {
+			docID = resp.Results[j].DocIDHex()
+		}
+		if docID == "" {
+			return FeedbackForm{}, fmt.Errorf("Result for position %d has no docid", i)
+		}
+		form.DocIDs = append(form.DocIDs, docID)
+	}
+
+	form.Normalize()
+	return form, nil
+}
@@ -0,0 +1,61 @@
+package clarifai
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestDedupeTagRequest(t *testing.T) {
+	req := TagRequest{URLs: []string{"a", "b", "a", "c", "b"}, LocalIDs: []string{"1", "2", "3", "4", "5"}}
+
+	deduped, d := DedupeTagRequest(req)
+
+	if !reflect.DeepEqual(deduped.URLs, []string{"a", "b", "c"}) || !reflect.DeepEqual(deduped.LocalIDs, []string{"1", "2", "4"}) {
+		t.Errorf("Repeated urls should be sent once, got %v %v", deduped.URLs, deduped.LocalIDs)
+	}
+	if !reflect.DeepEqual(d.Positions, [][]int{{0, 2}, {1, 4}, {3}}) {
+		t.Errorf("Unexpected positions: %v", d.Positions)
+	}
+
+	resp := &TagResp{StatusCode: StatusOK}
+	for i, u := range deduped.URLs {
+		resp.Results = append(resp.Results, TagResult{URL: u, LocalID: deduped.LocalIDs[i], DocID: big.NewInt(int64(10 + i)), DocIDString: "doc-" + u})
+	}
+
+	expanded, err := d.Expand(resp)
+
+	if err != nil {
+		t.Fatalf("Expand() should not return error: %v", err)
+	}
+	for i, result := range expanded.Results {
+		if result.URL != req.URLs[i] || result.LocalID != req.LocalIDs[i] {
+			t.Errorf("Expanded result %d should be %s (%s), got %s (%s)", i, req.URLs[i], req.LocalIDs[i], result.URL, result.LocalID)
+		}
+	}
+
+	form, err := d.FeedbackForm(resp, []int{0, 2, 4}, []string{"dog"}, nil)
+
+	if err != nil {
+		t.Fatalf("FeedbackForm() should not return error: %v", err)
+	}
+	if !reflect.DeepEqual(form.DocIDs, []string{"doc-a", "doc-b"}) || !reflect.DeepEqual(form.AddTags, []string{"dog"}) {
+		t.Errorf("Corrections to repeated inputs should share one docid each, got %+v", form)
+	}
+}
+
+func TestDedupeFeedbackFormMissingDocID(t *testing.T) {
+	_, d := DedupeTagRequest(TagRequest{URLs: []string{"a"}})
+
+	if _, err := d.FeedbackForm(&TagResp{Results: []TagResult{{URL: "a"}}}, []int{0}, []string{"dog"}, nil); err == nil {
+		t.Error("FeedbackForm() should fail for a result without a docid")
+	}
+}
+
+func TestDedupeExpandMismatch(t *testing.T) {
+	_, d := DedupeTagRequest(TagRequest{URLs: []string{"a", "b"}})
+
+	if _, err := d.Expand(&TagResp{}); err == nil {
+		t.Error("Expand() should fail when the response does not match the deduplicated request")
+	}
+}
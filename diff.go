@@ -0,0 +1,90 @@
+package clarifai
+
+// TagDiff lists the classes that changed for one image between two responses
+type TagDiff struct {
+	URL     string
+	LocalID string
+	Added   []string
+	Dropped []string
+}
+
+// DiffTags compares two responses for the same inputs, matching results by LocalID
+// (or URL when no local id was sent), and returns the classes at or above threshold
+// that newly appeared or dropped out. Images without changes are omitted.
+func DiffTags(prev, curr *TagResp, threshold float32) []TagDiff {
+	before := make(map[string]TagResult, len(prev.Results))
+	for _, result := range prev.Results {
+		before[resultKey(result)] = result
+	}
+
+	var diffs []TagDiff
+	seen := make(map[string]bool, len(curr.Results))
+
+	for _, result := range curr.Results {
+		key := resultKey(result)
+		seen[key] = true
+		if diff, changed := diffResult(before[key], result, threshold); changed {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	for _, result := range prev.Results {
+		if seen[resultKey(result)] {
+			continue
+		}
+		if diff, changed := diffResult(result, TagResult{URL: result.URL, LocalID: result.LocalID}, threshold); changed {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	return diffs
+}
+
+func resultKey(result TagResult) string {
+	if result.LocalID != "" {
+		return "local_id:" + result.LocalID
+	}
+	return "url:" + result.URL
+}
+
+func diffResult(prev, curr TagResult, threshold float32) (TagDiff, bool) {
+	diff := TagDiff{URL: curr.URL, LocalID: curr.LocalID}
+	before := classesAbove(prev, threshold)
+	after := classesAbove(curr, threshold)
+
+	for _, class := range orderedClasses(curr, after) {
+		if !before[class] {
+			diff.Added = append(diff.Added, class)
+		}
+	}
+
+	for _, class := range orderedClasses(prev, before) {
+		if !after[class] {
+			diff.Dropped = append(diff.Dropped, class)
+		}
+	}
+
+	return diff, diff.Added != nil || diff.Dropped != nil
+}
+
+func classesAbove(result TagResult, threshold float32) map[string]bool {
+	tag := result.Result.Tag
+	classes := make(map[string]bool, len(tag.Classes))
+	for i, class := range tag.Classes {
+		if i < len(tag.Probs) && tag.Probs[i] >= threshold {
+			classes[class] = true
+		}
+	}
+	return classes
+}
+
+// Helper function to list a set of classes in the order the result returned them
+func orderedClasses(result TagResult, set map[string]bool) []string {
+	var classes []string
+	for _, class := range result.Result.Tag.Classes {
+		if set[class] {
+			classes = append(classes, class)
+		}
+	}
+	return classes
+}
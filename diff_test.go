@@ -0,0 +1,42 @@
+package clarifai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func diffTestResult(url string, classes []string, probs []float32) TagResult {
+	result := TagResult{URL: url}
+	result.Result.Tag.Classes = classes
+	result.Result.Tag.Probs = probs
+	return result
+}
+
+func TestDiffTags(t *testing.T) {
+	prev := &TagResp{Results: []TagResult{
+		diffTestResult("http://a", []string{"train", "rail", "night"}, []float32{0.9, 0.8, 0.6}),
+		diffTestResult("http://b", []string{"dog"}, []float32{0.9}),
+	}}
+	curr := &TagResp{Results: []TagResult{
+		diffTestResult("http://b", []string{"dog"}, []float32{0.95}),
+		diffTestResult("http://a", []string{"train", "station", "rail"}, []float32{0.9, 0.85, 0.5}),
+	}}
+
+	diffs := DiffTags(prev, curr, 0.7)
+
+	expected := []TagDiff{{URL: "http://a", Added: []string{"station"}, Dropped: []string{"rail"}}}
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("DiffTags() should report classes crossing the threshold. Expected: %+v, Got: %+v", expected, diffs)
+	}
+}
+
+func TestDiffTagsMissingResult(t *testing.T) {
+	prev := &TagResp{Results: []TagResult{diffTestResult("http://a", []string{"train"}, []float32{0.9})}}
+	curr := &TagResp{}
+
+	diffs := DiffTags(prev, curr, 0.5)
+
+	if len(diffs) != 1 || !reflect.DeepEqual(diffs[0].Dropped, []string{"train"}) {
+		t.Errorf("DiffTags() should drop every class of a missing result. Got: %+v", diffs)
+	}
+}
@@ -0,0 +1,30 @@
+package clarifai
+
+import "math/big"
+
+// DocIDDecimal returns the docid in base 10, or an empty string if it is missing
+func (result TagResult) DocIDDecimal() string {
+	return formatDocID(result.DocID, 10)
+}
+
+// DocIDHex returns the docid in lowercase base 16, or an empty string if it is missing
+func (result TagResult) DocIDHex() string {
+	return formatDocID(result.DocID, 16)
+}
+
+// DocIDDecimal returns the docid in base 10, or an empty string if it is missing
+func (image ColorImage) DocIDDecimal() string {
+	return formatDocID(image.DocID, 10)
+}
+
+// DocIDHex returns the docid in lowercase base 16, or an empty string if it is missing
+func (image ColorImage) DocIDHex() string {
+	return formatDocID(image.DocID, 16)
+}
+
+func formatDocID(docID *big.Int, base int) string {
+	if docID == nil {
+		return ""
+	}
+	return docID.Text(base)
+}
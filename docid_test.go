@@ -0,0 +1,29 @@
+package clarifai
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestDocIDFormats(t *testing.T) {
+	docID, _ := new(big.Int).SetString("273996447814733945748816681886883360608", 10)
+	result := TagResult{DocID: docID}
+
+	if result.DocIDDecimal() != "273996447814733945748816681886883360608" {
+		t.Errorf("DocIDDecimal() returned the wrong value. Got: %v", result.DocIDDecimal())
+	}
+
+	if result.DocIDHex() != "ce21cbdd9b894e6af794813eb3fdaf60" {
+		t.Errorf("DocIDHex() returned the wrong value. Got: %v", result.DocIDHex())
+	}
+
+	if (ColorImage{DocID: docID}).DocIDHex() != result.DocIDHex() {
+		t.Error("ColorImage.DocIDHex() should match TagResult.DocIDHex()")
+	}
+}
+
+func TestDocIDFormatsNil(t *testing.T) {
+	if (TagResult{}).DocIDDecimal() != "" || (ColorImage{}).DocIDHex() != "" {
+		t.Error("DocID helpers should return an empty string for a missing docid")
+	}
+}
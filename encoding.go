@@ -0,0 +1,30 @@
+package clarifai
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// NormalizeBase64 converts pre-encoded image data into the standard, padded
+// base64 alphabet (RFC 4648 section 4) expected by the API. Input may use the
+// URL-safe alphabet, omit padding, or mix both; anything that does not decode
+// is rejected rather than sent corrupted.
+func NormalizeBase64(encoded string) (string, error) {
+	data, err := DecodeBase64(encoded)
+
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// DecodeBase64 decodes pre-encoded image data into the raw bytes expected by
+// EncodedData, accepting the same alphabets and padding as NormalizeBase64.
+func DecodeBase64(encoded string) ([]byte, error) {
+	s := strings.TrimSpace(encoded)
+	s = strings.NewReplacer("-", "+", "_", "/").Replace(s)
+	s = strings.TrimRight(s, "=")
+
+	return base64.RawStdEncoding.DecodeString(s)
+}
@@ -0,0 +1,49 @@
+package clarifai
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+func TestNormalizeBase64(t *testing.T) {
+	data := []byte{0xfb, 0xff, 0xfe, 0x01}
+	expected := base64.StdEncoding.EncodeToString(data)
+
+	inputs := []string{
+		expected,
+		base64.URLEncoding.EncodeToString(data),
+		base64.RawURLEncoding.EncodeToString(data),
+		base64.RawStdEncoding.EncodeToString(data),
+	}
+
+	for _, input := range inputs {
+		got, err := NormalizeBase64(input)
+
+		if err != nil || got != expected {
+			t.Errorf("NormalizeBase64(%q) should return %q. Got: %q, %v", input, expected, got, err)
+		}
+	}
+}
+
+func TestNormalizeBase64Invalid(t *testing.T) {
+	if _, err := NormalizeBase64("not base64!"); err == nil {
+		t.Error("NormalizeBase64() should return an err for invalid input")
+	}
+}
+
+func TestDecodeBase64(t *testing.T) {
+	data := []byte{0xfb, 0xff, 0xfe, 0x01}
+
+	for _, input := range []string{base64.StdEncoding.EncodeToString(data), base64.RawURLEncoding.EncodeToString(data)} {
+		got, err := DecodeBase64(input)
+
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("DecodeBase64(%q) should return %v. Got: %v, %v", input, data, got, err)
+		}
+	}
+
+	if _, err := DecodeBase64("not base64!"); err == nil {
+		t.Error("DecodeBase64() should return an err for invalid input")
+	}
+}
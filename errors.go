@@ -0,0 +1,82 @@
+package clarifai
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when Clarifai reports a failure, either through the HTTP
+// status or through a non-success status_code in the response body
+type APIError struct {
+	HTTPStatus    int
+	StatusCode    StatusCode
+	StatusMessage string
+}
+
+func (e *APIError) Error() string {
+	if e.StatusMessage == "" {
+		return e.StatusCode.String()
+	}
+	return e.StatusCode.String() + ": " + e.StatusMessage
+}
+
+// IsAuth reports whether the access token was missing, invalid or expired
+func (e *APIError) IsAuth() bool {
+	return e.HTTPStatus == http.StatusUnauthorized || strings.HasPrefix(string(e.StatusCode), "TOKEN_")
+}
+
+// IsThrottled reports whether the request was rejected by rate limiting
+func (e *APIError) IsThrottled() bool {
+	return e.HTTPStatus == http.StatusTooManyRequests || e.StatusCode == StatusThrottled
+}
+
+// IsBadRequest reports whether Clarifai rejected the request as malformed
+func (e *APIError) IsBadRequest() bool {
+	return e.HTTPStatus == http.StatusBadRequest || e.StatusCode == StatusAllError || e.StatusCode == StatusClientError
+}
+
+// statusBody is the status every API response carries
+type statusBody struct {
+	StatusCode    StatusCode `json:"status_code"`
+	StatusMessage string     `json:"status_msg"`
+}
+
+// Helper function to build an APIError from a failed response, using fallback
+// when the body does not say what went wrong
+func newAPIError(res *http.Response, fallback StatusCode) *APIError {
+	apiErr := &APIError{HTTPStatus: res.StatusCode, StatusCode: fallback}
+
+	// Error bodies are small; don't let a broken one be read without bound
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, 1<<16))
+
+	if err != nil {
+		return apiErr
+	}
+
+	status := statusBody{}
+	if json.Unmarshal(body, &status) == nil && status.StatusCode != "" {
+		apiErr.StatusCode = status.StatusCode
+		apiErr.StatusMessage = status.StatusMessage
+	}
+
+	return apiErr
+}
+
+// Helper function to turn a successful HTTP response whose body reports a failure into an APIError.
+// Partial failures still carry usable results, so they are left to the caller.
+func statusError(httpStatus int, body []byte) error {
+	status := statusBody{}
+	if json.Unmarshal(body, &status) != nil {
+		return nil
+	}
+
+	switch status.StatusCode {
+	case "", StatusOK, StatusPartialError:
+		return nil
+	}
+
+	return &APIError{HTTPStatus: httpStatus, StatusCode: status.StatusCode, StatusMessage: status.StatusMessage}
+}
@@ -0,0 +1,96 @@
+package clarifai
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIErrorFromBody(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.setAccessToken("token")
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+		fmt.Fprintln(w, `{"status_code": "ALL_ERROR", "status_msg": "All images in request have failed."}`)
+	})
+
+	_, err := client.Tag(TagRequest{URLs: []string{"http://www.clarifai.com/img/metro-north.jpg"}})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Tag() should return an *APIError, got %T: %v", err, err)
+	}
+	if apiErr.HTTPStatus != 400 || apiErr.StatusCode != StatusAllError || !apiErr.IsBadRequest() {
+		t.Errorf("Unexpected APIError: %+v", apiErr)
+	}
+	if apiErr.Error() != "ALL_ERROR: All images in request have failed." {
+		t.Errorf("Unexpected error message: %q", apiErr.Error())
+	}
+}
+
+func TestAPIErrorWithoutBody(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.setAccessToken("token")
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(429)
+	})
+
+	_, err := client.Info()
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.IsThrottled() {
+		t.Fatalf("Info() should return a throttled *APIError, got %v", err)
+	}
+	if err.Error() != "THROTTLED" {
+		t.Errorf("Error() should keep the legacy code when the body has no status, got %q", err.Error())
+	}
+}
+
+func TestAPIErrorOnSuccessStatus(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.setAccessToken("token")
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code": "TOKEN_EXPIRED", "status_msg": "Token is expired"}`)
+	})
+
+	_, err := client.Info()
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.IsAuth() {
+		t.Fatalf("Info() should return an auth *APIError, got %v", err)
+	}
+}
+
+func TestTransportErrorIsNotAPIError(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot("http://127.0.0.1:0")
+	client.setAccessToken("token")
+
+	_, err := client.Info()
+
+	var apiErr *APIError
+	if err == nil || errors.As(err, &apiErr) {
+		t.Errorf("A transport failure should not be an *APIError, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package clarifai
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Helper function to retag the low confidence results of tagres with the fallback model,
+// replacing those the fallback model is more confident about. The raw JSON is rebuilt
+// if any result was replaced. A failed fallback request keeps the primary results.
+func (client *Client) applyFallbackModel(ctx context.Context, req TagRequest, tagres *TagResp, raw []byte, opts *requestOptions) ([]byte, error) {
+	primary := req.Model
+	if primary == "" {
+		primary = tagres.Meta.Tag.Model
+	}
+
+	var low []int
+	for i, result := range tagres.Results {
+		if tagres.Results[i].Model == "" {
+			tagres.Results[i].Model = primary
+		}
+		if !result.StatusCode.IsError() && topProb(result) < opts.fallbackBelow {
+			low = append(low, i)
+		}
+	}
+
+	inputs := len(req.URLs) + len(req.EncodedData)
+	if len(low) == 0 || len(tagres.Results) != inputs {
+		return raw, nil
+	}
+
+	retag := req
+	retag.URLs, retag.EncodedData, retag.LocalIDs = keptInputs(low, req.URLs, req.EncodedData, req.LocalIDs)
+	retag.Model = opts.fallbackModel
+
+	retagOpts := opts.withKeySuffix("-" + opts.fallbackModel)
+	withoutProgress(retagOpts)
+
+	fallback, _, err := client.tagSplit(ctx, retag, retagOpts)
+
+	if err != nil {
+		client.log(LogWarn, "fallback model failed, keeping primary results", map[string]interface{}{
+			"model": opts.fallbackModel,
+			"error": err.Error(),
+		})
+		return raw, nil
+	}
+
+	if len(fallback.Results) != len(low) {
+		return raw, nil
+	}
+
+	replaced := false
+	for j, i := range low {
+		result := fallback.Results[j]
+		if result.StatusCode.IsError() || topProb(result) <= topProb(tagres.Results[i]) {
+			continue
+		}
+		result.Model = opts.fallbackModel
+		tagres.Results[i] = result
+		replaced = true
+	}
+
+	if !replaced {
+		return raw, nil
+	}
+	return json.Marshal(tagres)
+}
+
+// Helper function to get the highest prob of a result, zero if it has none
+func topProb(result TagResult) float32 {
+	var top float32
+	for _, prob := range result.Result.Tag.Probs {
+		if prob > top {
+			top = prob
+		}
+	}
+	return top
+}
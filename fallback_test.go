@@ -0,0 +1,96 @@
+package clarifai
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTagWithFallbackModel(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	// The primary model is unsure of "blurry" and "odd"; the fallback is only more sure of "blurry"
+	probs := map[string]map[string]float32{
+		"general": {"clear": 0.9, "blurry": 0.2, "odd": 0.3},
+		"nsfw":    {"blurry": 0.8, "odd": 0.1},
+	}
+	var fallbackURLs []string
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		var req TagRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model == "nsfw" {
+			fallbackURLs = req.URLs
+		}
+
+		resp := TagResp{StatusCode: StatusOK}
+		for i, u := range req.URLs {
+			result := TagResult{URL: u, LocalID: req.LocalIDs[i], StatusCode: StatusOK}
+			result.Result.Tag.Classes = []string{req.Model}
+			result.Result.Tag.Probs = []float32{probs[req.Model][u]}
+			resp.Results = append(resp.Results, result)
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	resp, err := client.Tag(TagRequest{
+		URLs:     []string{"clear", "blurry", "odd"},
+		LocalIDs: []string{"1", "2", "3"},
+		Model:    "general",
+	}, WithFallbackModel("nsfw", 0.5))
+
+	if err != nil {
+		t.Fatalf("Tag() should not return error: %v", err)
+	}
+
+	if len(fallbackURLs) != 2 || fallbackURLs[0] != "blurry" || fallbackURLs[1] != "odd" {
+		t.Errorf("Only low confidence images should be retagged, got %v", fallbackURLs)
+	}
+
+	expected := []string{"general", "nsfw", "general"}
+	for i, model := range expected {
+		if resp.Results[i].Model != model || resp.Results[i].Result.Tag.Classes[0] != model {
+			t.Errorf("Result %d should be from %s, got %s", i, model, resp.Results[i].Model)
+		}
+	}
+
+	// The winning model travels with its result
+	for _, result := range resp.Sample(2, 1).Results {
+		if result.Model != result.Result.Tag.Classes[0] {
+			t.Errorf("Sampled result should keep its model, got %s for tags from %s", result.Model, result.Result.Tag.Classes[0])
+		}
+	}
+	if resp.Results[1].LocalID != "2" {
+		t.Errorf("Replaced results should keep their local id, got %q", resp.Results[1].LocalID)
+	}
+}
+
+func TestTagWithFallbackModelNotNeeded(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	calls := 0
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		echoTagHandler(w, r)
+	})
+
+	resp, err := client.Tag(TagRequest{URLs: []string{"a"}, Model: "general"}, WithFallbackModel("nsfw", 0.5))
+
+	if err != nil {
+		t.Fatalf("Tag() should not return error: %v", err)
+	}
+	if calls != 1 || resp.Results[0].Model != "general" {
+		t.Errorf("Confident results should not be retagged, got %d calls and model %s", calls, resp.Results[0].Model)
+	}
+}
@@ -0,0 +1,33 @@
+package clarifai
+
+import "strings"
+
+// Normalize trims every docid, url and tag in the form, dropping empty entries and
+// repeats while keeping the first occurrence in order. Fields left with no entries
+// become nil so they are omitted from the request.
+func (form *FeedbackForm) Normalize() {
+	form.DocIDs = dedupeStrings(form.DocIDs)
+	form.URLs = dedupeStrings(form.URLs)
+	form.AddTags = dedupeStrings(form.AddTags)
+	form.RemoveTags = dedupeStrings(form.RemoveTags)
+	form.DissimilarDocIDs = dedupeStrings(form.DissimilarDocIDs)
+	form.SimilarDocIDs = dedupeStrings(form.SimilarDocIDs)
+	form.SearchClick = dedupeStrings(form.SearchClick)
+}
+
+// Helper function to trim values and drop the empty and repeated ones
+func dedupeStrings(values []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(values))
+
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+
+	return out
+}
@@ -0,0 +1,26 @@
+package clarifai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFeedbackFormNormalize(t *testing.T) {
+	form := FeedbackForm{
+		DocIDs:     []string{"abc", " abc ", "def", ""},
+		AddTags:    []string{"dog", "cat", "dog", "  "},
+		RemoveTags: []string{" ", ""},
+	}
+
+	form.Normalize()
+
+	if !reflect.DeepEqual(form.DocIDs, []string{"abc", "def"}) {
+		t.Errorf("Normalize() should trim and dedupe docids, got %q", form.DocIDs)
+	}
+	if !reflect.DeepEqual(form.AddTags, []string{"dog", "cat"}) {
+		t.Errorf("Normalize() should keep the first occurrence of each tag, got %q", form.AddTags)
+	}
+	if form.RemoveTags != nil || form.URLs != nil {
+		t.Errorf("Normalize() should leave fields with no entries nil, got %q and %q", form.RemoveTags, form.URLs)
+	}
+}
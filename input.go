@@ -0,0 +1,50 @@
+package clarifai
+
+// TagInput is a single image to tag, with an optional local id
+type TagInput struct {
+	URL     string
+	LocalID string
+}
+
+// ColorInput is a single image to color tag, with an optional local id
+type ColorInput struct {
+	URL     string
+	LocalID string
+}
+
+// NewTagRequest builds a TagRequest whose URLs and LocalIDs line up with items
+func NewTagRequest(items []TagInput) TagRequest {
+	urls := make([]string, len(items))
+	localIDs := make([]string, len(items))
+
+	for i, item := range items {
+		urls[i] = item.URL
+		localIDs[i] = item.LocalID
+	}
+
+	return TagRequest{URLs: urls, LocalIDs: alignedLocalIDs(localIDs)}
+}
+
+// NewColorRequest builds a ColorRequest whose URLs and LocalIDs line up with items
+func NewColorRequest(items []ColorInput) ColorRequest {
+	urls := make([]string, len(items))
+	localIDs := make([]string, len(items))
+
+	for i, item := range items {
+		urls[i] = item.URL
+		localIDs[i] = item.LocalID
+	}
+
+	return ColorRequest{URLs: urls, LocalIDs: alignedLocalIDs(localIDs)}
+}
+
+// Helper function to drop local ids entirely when none were given,
+// otherwise every position is kept so the ids stay aligned with the urls
+func alignedLocalIDs(localIDs []string) []string {
+	for _, id := range localIDs {
+		if id != "" {
+			return localIDs
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package clarifai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewTagRequest(t *testing.T) {
+	req := NewTagRequest([]TagInput{
+		{URL: "http://a", LocalID: "1"},
+		{URL: "http://b"},
+		{URL: "http://c", LocalID: "3"},
+	})
+
+	if !reflect.DeepEqual(req.URLs, []string{"http://a", "http://b", "http://c"}) {
+		t.Errorf("NewTagRequest() should keep the urls in order. Got: %v", req.URLs)
+	}
+
+	if !reflect.DeepEqual(req.LocalIDs, []string{"1", "", "3"}) {
+		t.Errorf("NewTagRequest() should align local ids with urls. Got: %v", req.LocalIDs)
+	}
+}
+
+func TestNewColorRequestWithoutLocalIDs(t *testing.T) {
+	req := NewColorRequest([]ColorInput{{URL: "http://a"}, {URL: "http://b"}})
+
+	if len(req.URLs) != 2 || req.LocalIDs != nil {
+		t.Errorf("NewColorRequest() should omit local ids when none are given. Got: %+v", req)
+	}
+}
@@ -0,0 +1,36 @@
+package clarifai
+
+import (
+	"context"
+	"errors"
+)
+
+// Helper function to retry a rejected tag request in the default language reported by /info/.
+// The original error is returned if the request was not rejected as invalid or no other language is available.
+func (client *Client) tagInDefaultLanguage(ctx context.Context, req TagRequest, opts *requestOptions, tagErr error) (*TagResp, []byte, error) {
+	var apiErr *APIError
+	if !errors.As(tagErr, &apiErr) || apiErr.StatusCode != StatusAllError {
+		return nil, nil, tagErr
+	}
+
+	info, err := client.info(ctx, opts)
+
+	if err != nil {
+		return nil, nil, tagErr
+	}
+
+	language := info.Results.DefaultLanguage
+	if language == "" || language == req.Language {
+		return nil, nil, tagErr
+	}
+
+	req.Language = language
+	tagres, raw, err := client.tag(ctx, req, opts.withKeySuffix("-"+language))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tagres.LanguageFallback = true
+	return tagres, raw, nil
+}
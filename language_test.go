@@ -0,0 +1,52 @@
+package clarifai
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTagLanguageFallback(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":{"default_language":"en"}}`)
+	})
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		var req TagRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Language != "en" {
+			w.WriteHeader(400)
+			return
+		}
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":[]}`)
+	})
+
+	req := TagRequest{URLs: []string{"http://www.clarifai.com/img/metro-north.jpg"}, Language: "xx"}
+
+	_, err := client.Tag(req)
+
+	if err == nil {
+		t.Error("Tag() should return an err for an unsupported language without the fallback option")
+	}
+
+	tagres, err := client.Tag(req, WithLanguageFallback())
+
+	if err != nil {
+		t.Fatalf("Tag() should fall back to the default language: %v", err)
+	}
+
+	if !tagres.LanguageFallback || tagres.Language != "en" {
+		t.Errorf("Tag() should report the fallback language. Got: %v, %v", tagres.Language, tagres.LanguageFallback)
+	}
+}
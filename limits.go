@@ -0,0 +1,91 @@
+package clarifai
+
+// Conservative limits used when /info/ is unavailable
+const (
+	DefaultMaxBatchSize  = 128
+	DefaultMaxImageBytes = 10485760
+	DefaultMaxImageSize  = 100000
+	DefaultMinImageSize  = 1
+)
+
+// Limits are the request limits the API enforces
+type Limits struct {
+	MaxBatchSize  int
+	MaxImageBytes int
+	MaxImageSize  int
+	MinImageSize  int
+}
+
+// DefaultLimits returns the documented fallback limits
+func DefaultLimits() Limits {
+	return Limits{
+		MaxBatchSize:  DefaultMaxBatchSize,
+		MaxImageBytes: DefaultMaxImageBytes,
+		MaxImageSize:  DefaultMaxImageSize,
+		MinImageSize:  DefaultMinImageSize,
+	}
+}
+
+// Limits returns the limits reported by /info/, fetched once and cached.
+// If /info/ fails, a warning is logged and the client's FallbackLimits are
+// returned instead; the fetch is retried on the next call.
+func (client *Client) Limits() Limits {
+	if limits, ok := client.cachedLimits(); ok {
+		return limits
+	}
+
+	info, err := client.Info()
+
+	if err != nil {
+		client.log(LogWarn, "info unavailable, using fallback limits", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return client.FallbackLimits
+	}
+
+	return client.setLimits(infoLimits(info))
+}
+
+// Helper function to get the limits cached from /info/, if any
+func (client *Client) cachedLimits() (Limits, bool) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.limits == nil {
+		return Limits{}, false
+	}
+	return *client.limits, true
+}
+
+// Helper function to read the limits out of an /info/ response
+func infoLimits(info *InfoResp) Limits {
+	return Limits{
+		MaxBatchSize:  info.Results.MaxBatchSize,
+		MaxImageBytes: info.Results.MaxImageBytes,
+		MaxImageSize:  info.Results.MaxImageSize,
+		MinImageSize:  info.Results.MinImageSize,
+	}
+}
+
+// Helper function to cache limits, keeping the fallback for any the API left out.
+// The limits as cached are returned.
+func (client *Client) setLimits(limits Limits) Limits {
+	// Missing fields keep their fallback so callers never see a zero limit
+	if limits.MaxBatchSize <= 0 {
+		limits.MaxBatchSize = client.FallbackLimits.MaxBatchSize
+	}
+	if limits.MaxImageBytes <= 0 {
+		limits.MaxImageBytes = client.FallbackLimits.MaxImageBytes
+	}
+	if limits.MaxImageSize <= 0 {
+		limits.MaxImageSize = client.FallbackLimits.MaxImageSize
+	}
+	if limits.MinImageSize <= 0 {
+		limits.MinImageSize = client.FallbackLimits.MinImageSize
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.limits = &limits
+	return limits
+}
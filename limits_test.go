@@ -0,0 +1,64 @@
+package clarifai
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLimitsCached(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	calls := 0
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":{"max_batch_size":64,"max_image_bytes":2048}}`)
+	})
+
+	client.Limits()
+	limits := client.Limits()
+
+	if limits.MaxBatchSize != 64 || limits.MaxImageBytes != 2048 || limits.MinImageSize != DefaultMinImageSize {
+		t.Errorf("Limits() should use /info/ values and fall back for missing ones. Got: %+v", limits)
+	}
+
+	if calls != 1 {
+		t.Errorf("Limits() should only call /info/ once. Got: %v calls", calls)
+	}
+}
+
+func TestLimitsFallback(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 16
+
+	defer server.Close()
+
+	var warned bool
+	client.Logger = func(level LogLevel, msg string, fields map[string]interface{}) {
+		warned = level == LogWarn
+	}
+
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	})
+
+	limits := client.Limits()
+
+	if limits.MaxBatchSize != 16 || limits.MaxImageBytes != DefaultMaxImageBytes {
+		t.Errorf("Limits() should return the fallback limits when /info/ fails. Got: %+v", limits)
+	}
+
+	if !warned {
+		t.Error("Limits() should log a warning when falling back")
+	}
+}
@@ -0,0 +1,38 @@
+package clarifai
+
+// LogLevel is the severity of an entry passed to a Logger
+type LogLevel int
+
+// Log levels, from least to most severe
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+// Logger receives structured log entries emitted by the client
+type Logger func(level LogLevel, msg string, fields map[string]interface{})
+
+// Helper function to send an entry to the client's logger, if one is set
+func (client *Client) log(level LogLevel, msg string, fields map[string]interface{}) {
+	if client.Logger != nil {
+		client.Logger(level, msg, fields)
+	}
+}
+
+// Helper function to log every failed result in a tag response. Nothing is logged on full success.
+func (client *Client) logFailedResults(tagres *TagResp) {
+	for i, result := range tagres.Results {
+		if !result.StatusCode.IsError() {
+			continue
+		}
+		client.log(client.FailureLogLevel, "tag result failed", map[string]interface{}{
+			"index":       i,
+			"url":         result.URL,
+			"local_id":    result.LocalID,
+			"status_code": result.StatusCode.String(),
+			"status_msg":  result.StatusMessage,
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package clarifai
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLogFailedResults(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FailureLogLevel = LogError
+
+	defer server.Close()
+
+	var entries []map[string]interface{}
+	var levels []LogLevel
+	client.Logger = func(level LogLevel, msg string, fields map[string]interface{}) {
+		levels = append(levels, level)
+		entries = append(entries, fields)
+	}
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"PARTIAL_ERROR","status_msg":"Some images failed","results":[{"url":"http://a","status_code":"OK","local_id":"1"},{"url":"http://b","status_code":"CLIENT_ERROR","status_msg":"bad image","local_id":"2"}]}`)
+	})
+
+	_, err := client.Tag(TagRequest{URLs: []string{"http://a", "http://b"}})
+
+	if err != nil {
+		t.Fatalf("Tag() should not return error with valid request: %q\n", err)
+	}
+
+	if len(entries) != 1 || entries[0]["url"] != "http://b" || entries[0]["local_id"] != "2" || levels[0] != LogError {
+		t.Errorf("Tag() should log one entry per failed result at the configured level. Got: %v %v", levels, entries)
+	}
+}
+
+func TestLogNothingOnSuccess(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+	logged := false
+	client.Logger = func(level LogLevel, msg string, fields map[string]interface{}) {
+		logged = true
+	}
+
+	client.logFailedResults(&TagResp{Results: []TagResult{{StatusCode: StatusOK}}})
+
+	if logged {
+		t.Error("logFailedResults() should not log when every result succeeded")
+	}
+}
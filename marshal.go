@@ -0,0 +1,53 @@
+package clarifai
+
+// Some API versions reject requests that omit empty arrays. When
+// Client.SendEmptySlices is set, the following fields are sent as []
+// instead of being left out:
+//
+//	TagRequest.LocalIDs   (local_ids)
+//	ColorRequest.LocalIDs (local_ids)
+
+type strictTagRequest struct {
+	URLs     []string `json:"url"`
+	LocalIDs []string `json:"local_ids"`
+	Model    string   `json:"model,omitempty"`
+	Language string   `json:"language,omitempty"`
+}
+
+type strictColorRequest struct {
+	URLs     []string `json:"url"`
+	LocalIDs []string `json:"local_ids"`
+}
+
+// Helper function to pick the body for a tag request: a multipart upload for
+// encoded images, otherwise JSON
+func (client *Client) tagBody(req TagRequest) interface{} {
+	if len(req.EncodedData) > 0 {
+		form := newMultipartBody(req.EncodedData, req.LocalIDs)
+		if req.Model != "" {
+			form.fields.Set("model", req.Model)
+		}
+		if req.Language != "" {
+			form.fields.Set("language", req.Language)
+		}
+		return form
+	}
+
+	if !client.SendEmptySlices {
+		return req
+	}
+	return strictTagRequest{nonNil(req.URLs), nonNil(req.LocalIDs), req.Model, req.Language}
+}
+
+// Helper function to pick the body for a color request: a multipart upload for
+// encoded images, otherwise JSON
+func (client *Client) colorBody(req ColorRequest) interface{} {
+	if len(req.EncodedData) > 0 {
+		return newMultipartBody(req.EncodedData, req.LocalIDs)
+	}
+
+	if !client.SendEmptySlices {
+		return req
+	}
+	return strictColorRequest{nonNil(req.URLs), nonNil(req.LocalIDs)}
+}
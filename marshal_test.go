@@ -0,0 +1,33 @@
+package clarifai
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTagBodyOmitsEmptySlices(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+
+	body, _ := json.Marshal(client.tagBody(TagRequest{URLs: []string{"http://a"}}))
+
+	if string(body) != `{"url":["http://a"]}` {
+		t.Errorf("tagBody() should omit empty local ids by default. Got: %s", body)
+	}
+}
+
+func TestTagBodySendsEmptySlices(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+	client.SendEmptySlices = true
+
+	body, _ := json.Marshal(client.tagBody(TagRequest{URLs: []string{"http://a"}, Model: "default"}))
+
+	if string(body) != `{"url":["http://a"],"local_ids":[],"model":"default"}` {
+		t.Errorf("tagBody() should send empty local ids as []. Got: %s", body)
+	}
+
+	body, _ = json.Marshal(client.colorBody(ColorRequest{URLs: []string{"http://a"}}))
+
+	if string(body) != `{"url":["http://a"],"local_ids":[]}` {
+		t.Errorf("colorBody() should send empty local ids as []. Got: %s", body)
+	}
+}
@@ -0,0 +1,86 @@
+package clarifai
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timestamp returns the processing time reported in the response metadata
+func (resp *TagResp) Timestamp() (time.Time, error) {
+	return parseTimestamp(resp.Meta.Tag.Timestamp)
+}
+
+// Timestamp returns the processing time reported in the response metadata, if present
+func (resp *ColorResp) Timestamp() (time.Time, error) {
+	return parseTimestamp(resp.Meta.Color.Timestamp)
+}
+
+// ProcessingMeta describes how a response was produced
+type ProcessingMeta struct {
+	// Timestamp is when the request was processed; zero if the response did not report it
+	Timestamp time.Time
+	// Model is the model that processed the request, e.g. "general-v1.3"
+	Model string
+	// Config identifies the model configuration
+	Config string
+	// Language is the language tags were requested in; empty for the default and for color responses
+	Language string
+	// LanguageFallback reports whether the tags are in the default language instead of Language
+	LanguageFallback bool
+}
+
+// Processing returns the response metadata. A missing or malformed timestamp is left zero.
+func (resp *TagResp) Processing() ProcessingMeta {
+	ts, _ := resp.Timestamp()
+	return ProcessingMeta{
+		Timestamp:        ts,
+		Model:            resp.Meta.Tag.Model,
+		Config:           resp.Meta.Tag.Config,
+		Language:         resp.Language,
+		LanguageFallback: resp.LanguageFallback,
+	}
+}
+
+// Processing returns the response metadata. A missing or malformed timestamp is left zero.
+func (resp *ColorResp) Processing() ProcessingMeta {
+	ts, _ := resp.Timestamp()
+	return ProcessingMeta{
+		Timestamp: ts,
+		Model:     resp.Meta.Color.Model,
+		Config:    resp.Meta.Color.Config,
+	}
+}
+
+// Helper function to convert fractional unix seconds (e.g. 1443807051.1546) into a time.Time
+func parseTimestamp(ts json.Number) (time.Time, error) {
+	raw := ts.String()
+	if raw == "" {
+		return time.Time{}, errors.New("Response does not include a timestamp")
+	}
+
+	parts := strings.SplitN(raw, ".", 2)
+	sec, err := strconv.ParseInt(parts[0], 10, 64)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var nsec int64
+	if len(parts) == 2 {
+		frac := parts[1]
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+		frac += strings.Repeat("0", 9-len(frac))
+		nsec, err = strconv.ParseInt(frac, 10, 64)
+
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return time.Unix(sec, nsec).UTC(), nil
+}
@@ -0,0 +1,69 @@
+package clarifai
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	ts, err := parseTimestamp(json.Number("1443807051.1546"))
+
+	if err != nil {
+		t.Errorf("parseTimestamp() should not return an err for a valid timestamp: %v", err)
+	}
+
+	expected := time.Unix(1443807051, 154600000).UTC()
+	if !ts.Equal(expected) {
+		t.Errorf("parseTimestamp() returned the wrong time. Expected: %v, Got: %v", expected, ts)
+	}
+}
+
+func TestParseTimestampMissing(t *testing.T) {
+	_, err := parseTimestamp(json.Number(""))
+
+	if err == nil {
+		t.Error("parseTimestamp() should return an err when no timestamp is present")
+	}
+}
+
+func TestColorRespTimestamp(t *testing.T) {
+	colorres := new(ColorResp)
+	err := json.Unmarshal([]byte(`{"status_code":"OK","meta":{"color":{"timestamp":1443807051,"model":"default","config":"abc"}},"results":[]}`), colorres)
+
+	if err != nil {
+		t.Errorf("ColorResp should decode meta: %v", err)
+	}
+
+	ts, err := colorres.Timestamp()
+
+	if err != nil || ts.Unix() != 1443807051 {
+		t.Errorf("Timestamp() should parse the color meta timestamp. Got: %v, %v", ts, err)
+	}
+}
+
+func TestTagRespProcessing(t *testing.T) {
+	tagres := new(TagResp)
+	json.Unmarshal([]byte(`{"status_code":"OK","meta":{"tag":{"timestamp":1443807051.1546,"model":"general-v1.3","config":"34fb1111b4d5f67cf1b8665ebc603704"}},"results":[]}`), tagres)
+	tagres.Language = "fr"
+
+	meta := tagres.Processing()
+
+	if meta.Model != "general-v1.3" || meta.Config != "34fb1111b4d5f67cf1b8665ebc603704" || meta.Language != "fr" {
+		t.Errorf("Processing() should copy the meta fields. Got: %+v", meta)
+	}
+	if !meta.Timestamp.Equal(time.Unix(1443807051, 154600000)) {
+		t.Errorf("Processing() should parse the timestamp. Got: %v", meta.Timestamp)
+	}
+}
+
+func TestColorRespProcessingMissingTimestamp(t *testing.T) {
+	colorres := new(ColorResp)
+	colorres.Meta.Color.Model = "default"
+
+	meta := colorres.Processing()
+
+	if !meta.Timestamp.IsZero() || meta.Model != "default" {
+		t.Errorf("Processing() should leave a missing timestamp zero. Got: %+v", meta)
+	}
+}
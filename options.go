@@ -0,0 +1,129 @@
+package clarifai
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// RequestOption customizes a single API call without mutating the shared client
+type RequestOption func(*requestOptions)
+
+// requestOptions holds the per-call settings collected from RequestOptions
+type requestOptions struct {
+	accessToken      string
+	languageFallback bool
+	verifyURLs       bool
+	verifyWorkers    int
+	verifyTimeout    time.Duration
+	provenance       bool
+	noRetry          bool
+	fallbackModel    string
+	fallbackBelow    float32
+	idempotent       bool
+	idempotencyKey   string
+	throttle         bool
+	progress         *progress
+	transcodeFormat  ImageFormat
+	transcodeQuality int
+}
+
+// WithAccessToken overrides the client's access token for a single request.
+// The override is never refreshed; a rejected token surfaces as TOKEN_INVALID.
+func WithAccessToken(token string) RequestOption {
+	return func(o *requestOptions) {
+		o.accessToken = token
+	}
+}
+
+// WithLanguageFallback retries a tag request in the API's default language
+// when the requested language is rejected
+func WithLanguageFallback() RequestOption {
+	return func(o *requestOptions) {
+		o.languageFallback = true
+	}
+}
+
+// WithURLVerification checks every url with VerifyURLs before sending the request,
+// failing fast with a *URLCheckError if any of them is unreachable or not an image
+func WithURLVerification(concurrency int, timeout time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.verifyURLs = true
+		o.verifyWorkers = concurrency
+		o.verifyTimeout = timeout
+	}
+}
+
+// WithProvenance records, in TagResult.Provenance, which request of a merged batch produced each result
+func WithProvenance() RequestOption {
+	return func(o *requestOptions) {
+		o.provenance = true
+	}
+}
+
+// NoRetry sends the request exactly once. The only retry the client performs is
+// resending a request after refreshing a rejected access token; with NoRetry the
+// token is not refreshed and TOKEN_INVALID is returned instead.
+func NoRetry() RequestOption {
+	return func(o *requestOptions) {
+		o.noRetry = true
+	}
+}
+
+// WithFallbackModel retags, with model, every image whose most probable tag is below
+// threshold, keeping whichever model's tags are more confident. TagResult.Model records
+// the model kept for each result. Images under the threshold are tagged twice, so each
+// of them costs an extra operation against the account's usage.
+func WithFallbackModel(model string, threshold float32) RequestOption {
+	return func(o *requestOptions) {
+		o.fallbackModel = model
+		o.fallbackBelow = threshold
+	}
+}
+
+// WithIdempotencyKey sends key as the Idempotency-Key header of Tag, Color and Feedback
+// requests, so a proxy that deduplicates on it never processes a resent request twice.
+// The key is reused when the request is resent after a token refresh. An empty key
+// generates a new random one for every call. Requests split into chunks, or resent in
+// another language or model, send different bodies and so get the key with a suffix.
+func WithIdempotencyKey(key string) RequestOption {
+	return func(o *requestOptions) {
+		o.idempotent = true
+		o.idempotencyKey = key
+	}
+}
+
+// Helper function to collapse a list of options into their settings
+func newRequestOptions(opts []RequestOption) *requestOptions {
+	o := &requestOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// Helper function to generate the idempotency key of a call that asked for one without giving it
+func (o *requestOptions) ensureIdempotencyKey() error {
+	if !o.idempotent || o.idempotencyKey != "" {
+		return nil
+	}
+
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+
+	o.idempotencyKey = hex.EncodeToString(key)
+	return nil
+}
+
+// Helper function to copy the options for a distinct request of the same call
+func (o *requestOptions) withKeySuffix(suffix string) *requestOptions {
+	c := *o
+	if c.idempotencyKey != "" {
+		c.idempotencyKey += suffix
+	}
+	return &c
+}
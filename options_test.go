@@ -0,0 +1,185 @@
+package clarifai
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithAccessTokenOverridesHeader(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.setAccessToken("shared")
+
+	defer server.Close()
+
+	var got string
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":{}}`)
+	})
+
+	_, err := client.Info(WithAccessToken("tenant"))
+
+	if err != nil {
+		t.Errorf("Info() should not return an err upon success: %v", err)
+	}
+
+	if got != "Bearer tenant" {
+		t.Errorf("WithAccessToken() should set the Authorization header. Expected: Bearer tenant, Got: %v", got)
+	}
+
+	if client.AccessToken != "shared" {
+		t.Errorf("WithAccessToken() should not mutate the client. Expected: shared, Got: %v", client.AccessToken)
+	}
+}
+
+func TestWithAccessTokenSkipsRefresh(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	refreshed := false
+	mux.HandleFunc("/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		refreshed = true
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"access_token":"1234567890abcdefg","expires_in":36000,"scope": "api_access", "token_type": "Bearer"}`)
+	})
+
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(401)
+	})
+
+	_, err := client.Info(WithAccessToken("tenant"))
+
+	if err == nil || err.Error() != "TOKEN_INVALID" {
+		t.Errorf("Info() should return TOKEN_INVALID for a rejected override, Got: %v", err)
+	}
+
+	if refreshed {
+		t.Error("WithAccessToken() should not trigger a token refresh")
+	}
+}
+
+func TestNoRetry(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	refreshed := false
+	mux.HandleFunc("/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		refreshed = true
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"access_token":"1234567890abcdefg","expires_in":36000,"scope": "api_access", "token_type": "Bearer"}`)
+	})
+
+	calls := 0
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(401)
+	})
+
+	_, err := client.Info(NoRetry())
+
+	if err == nil || err.Error() != "TOKEN_INVALID" {
+		t.Errorf("Info() should return TOKEN_INVALID without retrying. Got: %v", err)
+	}
+
+	if refreshed || calls != 1 {
+		t.Errorf("NoRetry() should send the request once without refreshing. Got: %v calls, refreshed %v", calls, refreshed)
+	}
+}
+
+func TestWithIdempotencyKeyReusedOnRetry(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"access_token":"1234567890abcdefg","expires_in":36000,"scope": "api_access", "token_type": "Bearer"}`)
+	})
+
+	var keys []string
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			w.WriteHeader(401)
+			return
+		}
+		echoTagHandler(w, r)
+	})
+
+	req := TagRequest{URLs: []string{"http://example.com/a.jpg"}}
+	if _, err := client.Tag(req, WithIdempotencyKey("")); err != nil {
+		t.Fatalf("Tag() should not return error: %v", err)
+	}
+
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("The generated key should be resent unchanged after the token refresh, got %q", keys)
+	}
+
+	if _, err := client.Tag(req, WithIdempotencyKey("")); err != nil {
+		t.Fatalf("Tag() should not return error: %v", err)
+	}
+
+	if keys[2] == keys[0] {
+		t.Error("Every call should generate its own key")
+	}
+}
+
+func TestWithIdempotencyKeyPerChunk(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 1
+
+	defer server.Close()
+
+	keys := make(chan string, 2)
+	mux.HandleFunc("/v1/color", func(w http.ResponseWriter, r *http.Request) {
+		keys <- r.Header.Get("Idempotency-Key")
+		fmt.Fprintln(w, `{"status_code":"OK","results":[{}]}`)
+	})
+
+	if _, err := client.Color(ColorRequest{URLs: []string{"a", "b"}}, WithIdempotencyKey("call")); err != nil {
+		t.Fatalf("Color() should not return error: %v", err)
+	}
+
+	first, second := <-keys, <-keys
+	if first == second || first[:5] != "call-" || second[:5] != "call-" {
+		t.Errorf("Each chunk should get the key with its own suffix, got %q and %q", first, second)
+	}
+}
+
+func TestWithoutIdempotencyKey(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Header["Idempotency-Key"]; ok {
+			t.Error("No key should be sent unless asked for")
+		}
+		echoTagHandler(w, r)
+	})
+
+	client.Tag(TagRequest{URLs: []string{"http://example.com/a.jpg"}})
+}
@@ -0,0 +1,54 @@
+package clarifai
+
+// OrderByInput returns a copy of resp whose results line up one-to-one with the inputs of req.
+// Results are matched by local id when req has LocalIDs and by URL otherwise, so responses
+// merged from chunks that completed in any order can be indexed positionally.
+// Inputs without a result get a CLIENT_ERROR placeholder and mark the response PARTIAL_ERROR.
+func OrderByInput(req TagRequest, resp *TagResp) *TagResp {
+	ordered := *resp
+	n := len(req.URLs)
+	if len(req.EncodedData) > n {
+		n = len(req.EncodedData)
+	}
+	ordered.Results = make([]TagResult, n)
+
+	byLocalID := len(req.LocalIDs) == n && n > 0
+
+	// Queue results per key so repeated URLs are matched in the order they were returned
+	pending := make(map[string][]TagResult)
+	for _, result := range resp.Results {
+		key := result.URL
+		if byLocalID {
+			key = result.LocalID
+		}
+		pending[key] = append(pending[key], result)
+	}
+
+	for i := range ordered.Results {
+		var url, localID string
+		if i < len(req.URLs) {
+			url = req.URLs[i]
+		}
+		if i < len(req.LocalIDs) {
+			localID = req.LocalIDs[i]
+		}
+
+		key := url
+		if byLocalID {
+			key = localID
+		}
+
+		if queue := pending[key]; len(queue) > 0 {
+			ordered.Results[i] = queue[0]
+			pending[key] = queue[1:]
+		} else {
+			ordered.Results[i] = TagResult{URL: url, LocalID: localID, StatusCode: StatusClientError, StatusMessage: "Missing from response", Model: req.Model}
+		}
+
+		if ordered.Results[i].StatusCode.IsError() && ordered.StatusCode == StatusOK {
+			ordered.StatusCode = StatusPartialError
+		}
+	}
+
+	return &ordered
+}
@@ -0,0 +1,72 @@
+package clarifai
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestOrderByInputShuffledChunks(t *testing.T) {
+	req := TagRequest{}
+	for i := 0; i < 10; i++ {
+		req.URLs = append(req.URLs, "http://example.com/"+strconv.Itoa(i)+".jpg")
+		req.LocalIDs = append(req.LocalIDs, "id-"+strconv.Itoa(i))
+	}
+
+	// Chunks of three complete in a random order, and one input never comes back
+	var chunks [][]TagResult
+	for start := 0; start < len(req.URLs); start += 3 {
+		var chunk []TagResult
+		for i := start; i < start+3 && i < len(req.URLs); i++ {
+			if i == 7 {
+				continue
+			}
+			chunk = append(chunk, TagResult{URL: req.URLs[i], LocalID: req.LocalIDs[i], StatusCode: StatusOK})
+		}
+		chunks = append(chunks, chunk)
+	}
+	rand.New(rand.NewSource(1)).Shuffle(len(chunks), func(i, j int) { chunks[i], chunks[j] = chunks[j], chunks[i] })
+
+	resp := &TagResp{StatusCode: StatusOK}
+	for _, chunk := range chunks {
+		resp.Results = append(resp.Results, chunk...)
+	}
+
+	ordered := OrderByInput(req, resp)
+
+	if len(ordered.Results) != len(req.URLs) {
+		t.Fatalf("OrderByInput() should return one result per input, got %d", len(ordered.Results))
+	}
+	for i, result := range ordered.Results {
+		if result.LocalID != req.LocalIDs[i] || result.URL != req.URLs[i] {
+			t.Errorf("Result %d is for %s (%s), want %s", i, result.LocalID, result.URL, req.LocalIDs[i])
+		}
+	}
+	if ordered.Results[7].StatusCode != StatusClientError {
+		t.Errorf("The missing input should get a CLIENT_ERROR placeholder, got %s", ordered.Results[7].StatusCode)
+	}
+	if ordered.StatusCode != StatusPartialError {
+		t.Errorf("A placeholder should mark the response PARTIAL_ERROR, got %s", ordered.StatusCode)
+	}
+	if len(resp.Results) != 9 {
+		t.Error("OrderByInput() should not modify the response it was given")
+	}
+}
+
+func TestOrderByInputDuplicateURLs(t *testing.T) {
+	req := TagRequest{URLs: []string{"a", "b", "a"}}
+	resp := &TagResp{StatusCode: StatusOK, Results: []TagResult{
+		{URL: "b", StatusCode: StatusOK},
+		{URL: "a", StatusCode: StatusOK, LocalID: "first"},
+		{URL: "a", StatusCode: StatusOK, LocalID: "second"},
+	}}
+
+	ordered := OrderByInput(req, resp)
+
+	if ordered.Results[0].LocalID != "first" || ordered.Results[1].URL != "b" || ordered.Results[2].LocalID != "second" {
+		t.Errorf("Repeated URLs should be matched in response order, got %+v", ordered.Results)
+	}
+	if ordered.StatusCode != StatusOK {
+		t.Errorf("Complete results should keep the OK status, got %s", ordered.StatusCode)
+	}
+}
@@ -0,0 +1,46 @@
+package clarifai
+
+import "encoding/json"
+
+// tagResultJSON has the fields of TagResult without its UnmarshalJSON method
+type tagResultJSON TagResult
+
+// UnmarshalJSON decodes a tag result, keeping the probs at full precision for Probs64
+func (result *TagResult) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*tagResultJSON)(result)); err != nil {
+		return err
+	}
+
+	raw := struct {
+		Result struct {
+			Tag struct {
+				Probs []float64 `json:"probs"`
+			} `json:"tag"`
+		} `json:"result"`
+	}{}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	result.probs64 = raw.Result.Tag.Probs
+	return nil
+}
+
+// Probs64 returns the probs as decoded from the response, without rounding them to float32.
+// SortByProb, Limit and FilterByProb keep them aligned with Result.Tag.Probs. If the result
+// was not decoded from JSON, or its probs were resized by hand, the float32 probs are widened instead.
+func (result TagResult) Probs64() []float64 {
+	probs := result.Result.Tag.Probs
+	out := make([]float64, len(probs))
+
+	if len(result.probs64) == len(probs) {
+		copy(out, result.probs64)
+		return out
+	}
+
+	for i, prob := range probs {
+		out[i] = float64(prob)
+	}
+	return out
+}
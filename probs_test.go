@@ -0,0 +1,55 @@
+package clarifai
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProbs64(t *testing.T) {
+	var result TagResult
+	err := json.Unmarshal([]byte(`{"docid": 123, "result": {"tag": {"classes": ["a", "b"], "probs": [0.1234567891234, 0.9876543219876]}}}`), &result)
+
+	if err != nil {
+		t.Fatalf("Unmarshal should not return error: %v", err)
+	}
+
+	if result.DocID == nil || result.DocID.Int64() != 123 {
+		t.Errorf("Other fields should still be decoded, got docid %v", result.DocID)
+	}
+	if result.Result.Tag.Probs[0] != float32(0.1234567891234) {
+		t.Errorf("Probs should stay float32, got %v", result.Result.Tag.Probs)
+	}
+
+	probs := result.Probs64()
+	if probs[0] != 0.1234567891234 || probs[1] != 0.9876543219876 {
+		t.Errorf("Probs64() should keep full precision, got %v", probs)
+	}
+}
+
+func TestProbs64AfterSort(t *testing.T) {
+	var result TagResult
+	json.Unmarshal([]byte(`{"result": {"tag": {"classes": ["a", "b"], "catids": ["1", "2"], "probs": [0.1234567891234, 0.9876543219876]}}}`), &result)
+
+	result.SortByProb()
+
+	probs := result.Probs64()
+	if len(probs) != 2 || probs[0] != 0.9876543219876 || probs[1] != 0.1234567891234 {
+		t.Errorf("Probs64() should follow the reordered probs at full precision, got %v", probs)
+	}
+
+	result.FilterByProb(0.5)
+	result.Limit(1)
+
+	if probs := result.Probs64(); len(probs) != 1 || probs[0] != 0.9876543219876 {
+		t.Errorf("Probs64() should keep full precision after filtering, got %v", probs)
+	}
+}
+
+func TestProbs64WithoutJSON(t *testing.T) {
+	result := TagResult{}
+	result.Result.Tag.Probs = []float32{0.5}
+
+	if probs := result.Probs64(); len(probs) != 1 || probs[0] != 0.5 {
+		t.Errorf("Probs64() should widen float32 probs, got %v", probs)
+	}
+}
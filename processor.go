@@ -0,0 +1,60 @@
+package clarifai
+
+// ResultProcessor post-processes the response of every Tag call, e.g. to enrich,
+// filter or log it. Returning an error fails the Tag call.
+type ResultProcessor interface {
+	Process(*TagResp) error
+}
+
+// ResultProcessorFunc adapts a function to the ResultProcessor interface
+type ResultProcessorFunc func(*TagResp) error
+
+// Process calls fn(resp)
+func (fn ResultProcessorFunc) Process(resp *TagResp) error {
+	return fn(resp)
+}
+
+// AddResultProcessor registers a processor to run after each Tag call, in registration order
+func (client *Client) AddResultProcessor(processor ResultProcessor) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.processors = append(client.processors, processor)
+}
+
+// Helper function to run the registered processors over a response
+func (client *Client) processResults(tagres *TagResp) error {
+	client.mu.Lock()
+	processors := client.processors
+	client.mu.Unlock()
+
+	for _, processor := range processors {
+		if err := processor.Process(tagres); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ThresholdFilter is a ResultProcessor dropping tags below MinProb
+type ThresholdFilter struct {
+	MinProb float32
+}
+
+// Process filters every result in the response
+func (f ThresholdFilter) Process(resp *TagResp) error {
+	for i := range resp.Results {
+		resp.Results[i].FilterByProb(f.MinProb)
+	}
+	return nil
+}
+
+// ProbSorter is a ResultProcessor ordering every result's tags from most to least probable
+type ProbSorter struct{}
+
+// Process sorts every result in the response
+func (ProbSorter) Process(resp *TagResp) error {
+	for i := range resp.Results {
+		resp.Results[i].SortByProb()
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package clarifai
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResultProcessors(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":[{"url":"http://a","status_code":"OK","result":{"tag":{"classes":["rail","train","blur"],"probs":[0.8,0.9,0.1]}}}]}`)
+	})
+
+	var order []string
+	client.AddResultProcessor(ThresholdFilter{MinProb: 0.5})
+	client.AddResultProcessor(ProbSorter{})
+	client.AddResultProcessor(ResultProcessorFunc(func(resp *TagResp) error {
+		order = resp.Results[0].Result.Tag.Classes
+		return nil
+	}))
+
+	_, err := client.Tag(TagRequest{URLs: []string{"http://a"}})
+
+	if err != nil {
+		t.Fatalf("Tag() should not return error with valid request: %q\n", err)
+	}
+
+	if len(order) != 2 || order[0] != "train" || order[1] != "rail" {
+		t.Errorf("Tag() should run processors in registration order. Got: %v", order)
+	}
+}
+
+func TestResultProcessorError(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":[]}`)
+	})
+
+	client.AddResultProcessor(ResultProcessorFunc(func(resp *TagResp) error {
+		return errors.New("rejected")
+	}))
+
+	_, err := client.Tag(TagRequest{URLs: []string{"http://a"}})
+
+	if err == nil || err.Error() != "rejected" {
+		t.Errorf("Tag() should return the processor's err. Got: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package clarifai
+
+import "sync"
+
+// ProgressKind is the kind of a ProgressEvent
+type ProgressKind int
+
+// The progress events of a request in a batch
+const (
+	ChunkStarted ProgressKind = iota
+	ChunkCompleted
+	ChunkFailed
+)
+
+func (k ProgressKind) String() string {
+	switch k {
+	case ChunkStarted:
+		return "started"
+	case ChunkCompleted:
+		return "completed"
+	case ChunkFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// ProgressEvent reports a request of a batch starting, completing or failing
+type ProgressEvent struct {
+	Kind ProgressKind
+	// Chunk is the index of the request among the Chunks the batch was split into
+	Chunk  int
+	Chunks int
+	// Inputs is the number of inputs the request carries
+	Inputs int
+	// Err is why the request failed, for ChunkFailed events
+	Err error
+}
+
+// WithProgress sends a ProgressEvent to events as each request of the call starts and
+// finishes, and closes events when the call returns. It applies to Tag, Color, their
+// Context, Raw and Validated variants, BatchByModel and the stream methods; a request
+// that is not split is reported as a single chunk. Events are never waited for: an
+// event that does not fit in the buffer of events is dropped, so give it room for
+// two events per chunk to receive all of them.
+func WithProgress(events chan<- ProgressEvent) RequestOption {
+	// Every request of the call shares one progress, so events is closed only once
+	p := &progress{events: events}
+	return func(o *requestOptions) {
+		o.progress = p
+	}
+}
+
+// withoutProgress stops a request from reporting to the progress of the call it is part of
+func withoutProgress(o *requestOptions) {
+	o.progress = nil
+}
+
+// progress delivers the events of a call; a nil progress reports nothing.
+// Once events is closed nothing more is sent, even if the option is reused.
+type progress struct {
+	events chan<- ProgressEvent
+	mu     sync.Mutex
+	closed bool
+}
+
+// Helper function to report a chunk around the request sent by send
+func (p *progress) track(chunk, chunks, inputs int, send func() error) {
+	p.send(ProgressEvent{Kind: ChunkStarted, Chunk: chunk, Chunks: chunks, Inputs: inputs})
+
+	if err := send(); err != nil {
+		p.send(ProgressEvent{Kind: ChunkFailed, Chunk: chunk, Chunks: chunks, Inputs: inputs, Err: err})
+		return
+	}
+	p.send(ProgressEvent{Kind: ChunkCompleted, Chunk: chunk, Chunks: chunks, Inputs: inputs})
+}
+
+// Helper function to deliver an event without blocking, dropping it if events is full
+func (p *progress) send(event ProgressEvent) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	select {
+	case p.events <- event:
+	default:
+	}
+}
+
+// Helper function to close events once the call is done
+func (p *progress) close() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.closed {
+		close(p.events)
+		p.closed = true
+	}
+}
@@ -0,0 +1,138 @@
+package clarifai
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Helper function to count the events of a closed progress channel by kind
+func collectProgress(t *testing.T, events chan ProgressEvent) map[ProgressKind]int {
+	counts := make(map[ProgressKind]int)
+	for event := range events {
+		counts[event.Kind]++
+		if event.Kind == ChunkFailed && event.Err == nil {
+			t.Errorf("A failed chunk should carry its error, got %+v", event)
+		}
+	}
+	return counts
+}
+
+func TestTagProgress(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 2
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var req TagRequest
+		json.Unmarshal(body, &req)
+		if req.URLs[0] == "c" {
+			w.WriteHeader(500)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		echoTagHandler(w, r)
+	})
+
+	events := make(chan ProgressEvent, 6)
+	_, err := client.Tag(TagRequest{URLs: []string{"a", "b", "c", "d", "e"}}, WithProgress(events))
+
+	if _, ok := err.(*ChunkError); !ok {
+		t.Fatalf("Tag() should return a *ChunkError, got %v", err)
+	}
+
+	counts := collectProgress(t, events)
+	if counts[ChunkStarted] != 3 || counts[ChunkCompleted] != 2 || counts[ChunkFailed] != 1 {
+		t.Errorf("Expected 3 started, 2 completed and 1 failed chunk, got %v", counts)
+	}
+}
+
+func TestProgressNeverBlocks(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", echoTagHandler)
+
+	// Nobody reads the unbuffered channel, so every event is dropped
+	events := make(chan ProgressEvent)
+	if _, err := client.Tag(TagRequest{URLs: []string{"a"}}, WithProgress(events)); err != nil {
+		t.Fatalf("Tag() should not return error: %v", err)
+	}
+
+	if _, open := <-events; open {
+		t.Error("The progress channel should be closed without any event")
+	}
+
+	// The channel is closed even when the request is rejected before it is sent
+	events = make(chan ProgressEvent, 2)
+	client.Tag(TagRequest{}, WithProgress(events))
+
+	if counts := collectProgress(t, events); len(counts) != 0 {
+		t.Errorf("A rejected request should report no chunks, got %v", counts)
+	}
+}
+
+func TestBatchByModelProgress(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", echoTagHandler)
+
+	events := make(chan ProgressEvent, 4)
+	items := []ModelInput{{URL: "a", Model: "general"}, {URL: "b", Model: "nsfw"}, {URL: "c", Model: "general"}}
+
+	if _, err := client.BatchByModel(items, WithProgress(events)); err != nil {
+		t.Fatalf("BatchByModel() should not return error: %v", err)
+	}
+
+	inputs := 0
+	for event := range events {
+		if event.Chunks != 2 {
+			t.Errorf("Each model should be one chunk of two, got %+v", event)
+		}
+		if event.Kind == ChunkCompleted {
+			inputs += event.Inputs
+		}
+	}
+	if inputs != len(items) {
+		t.Errorf("Completed chunks should cover every input, got %d", inputs)
+	}
+}
+
+func TestTagStreamJSONProgress(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 1
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", echoTagHandler)
+
+	events := make(chan ProgressEvent, 4)
+	if err := client.TagStreamJSON([]string{"a", "b"}, ioutil.Discard, WithProgress(events)); err != nil {
+		t.Fatalf("TagStreamJSON() should not return error: %v", err)
+	}
+
+	counts := collectProgress(t, events)
+	if counts[ChunkStarted] != 2 || counts[ChunkCompleted] != 2 {
+		t.Errorf("Expected 2 started and 2 completed chunks, got %v", counts)
+	}
+}
@@ -0,0 +1,74 @@
+package clarifai
+
+// TagQuery filters the results of a TagResp. Conditions are combined with AND semantics.
+type TagQuery struct {
+	resp    *TagResp
+	classes []string
+	minProb float32
+}
+
+// Query starts a filter over the response's results
+func (resp *TagResp) Query() *TagQuery {
+	return &TagQuery{resp: resp}
+}
+
+// WithClass keeps results tagged with class at or above the query's minimum prob
+func (q *TagQuery) WithClass(class string) *TagQuery {
+	q.classes = append(q.classes, class)
+	return q
+}
+
+// MinProb sets the minimum prob a class must reach to match. Without any
+// WithClass conditions, results match if any of their tags reaches it.
+func (q *TagQuery) MinProb(prob float32) *TagQuery {
+	q.minProb = prob
+	return q
+}
+
+// Results returns the matching results in their original order
+func (q *TagQuery) Results() []TagResult {
+	var matches []TagResult
+	for _, result := range q.resp.Results {
+		if q.matches(result) {
+			matches = append(matches, result)
+		}
+	}
+	return matches
+}
+
+// URLs returns the urls of the matching results in their original order
+func (q *TagQuery) URLs() []string {
+	var urls []string
+	for _, result := range q.Results() {
+		urls = append(urls, result.URL)
+	}
+	return urls
+}
+
+func (q *TagQuery) matches(result TagResult) bool {
+	tag := result.Result.Tag
+	probs := make(map[string]float32, len(tag.Classes))
+
+	for i, class := range tag.Classes {
+		if i < len(tag.Probs) {
+			probs[class] = tag.Probs[i]
+		}
+	}
+
+	if len(q.classes) == 0 {
+		for _, prob := range probs {
+			if prob >= q.minProb {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, class := range q.classes {
+		prob, ok := probs[class]
+		if !ok || prob < q.minProb {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package clarifai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func queryTestResp() *TagResp {
+	results := make([]TagResult, 3)
+	results[0].URL = "http://a"
+	results[0].Result.Tag.Classes = []string{"dog", "grass"}
+	results[0].Result.Tag.Probs = []float32{0.9, 0.8}
+	results[1].URL = "http://b"
+	results[1].Result.Tag.Classes = []string{"dog", "sofa"}
+	results[1].Result.Tag.Probs = []float32{0.6, 0.95}
+	results[2].URL = "http://c"
+	results[2].Result.Tag.Classes = []string{"cat"}
+	results[2].Result.Tag.Probs = []float32{0.5}
+	return &TagResp{Results: results}
+}
+
+func TestQueryWithClassMinProb(t *testing.T) {
+	urls := queryTestResp().Query().WithClass("dog").MinProb(0.7).URLs()
+
+	if !reflect.DeepEqual(urls, []string{"http://a"}) {
+		t.Errorf("Query() should only match dog above 0.7. Got: %v", urls)
+	}
+}
+
+func TestQueryMultipleClasses(t *testing.T) {
+	urls := queryTestResp().Query().WithClass("dog").WithClass("sofa").URLs()
+
+	if !reflect.DeepEqual(urls, []string{"http://b"}) {
+		t.Errorf("Query() should require every class. Got: %v", urls)
+	}
+}
+
+func TestQueryMinProbOnly(t *testing.T) {
+	urls := queryTestResp().Query().MinProb(0.85).URLs()
+
+	if !reflect.DeepEqual(urls, []string{"http://a", "http://b"}) {
+		t.Errorf("Query() should match any tag above the minimum. Got: %v", urls)
+	}
+}
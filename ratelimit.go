@@ -0,0 +1,78 @@
+package clarifai
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Rate limit headers sent by the API
+const (
+	rateLimitHeader     = "X-RateLimit-Limit"
+	rateLimitRemaining  = "X-RateLimit-Remaining"
+	rateLimitResetAfter = "X-RateLimit-Reset"
+)
+
+// RateLimit is the throttling state reported with a response.
+// A nil field means the response did not report it, which is not the same as zero.
+type RateLimit struct {
+	// Limit is the number of calls allowed in the current window
+	Limit *int
+	// Remaining is the number of calls left in the current window
+	Remaining *int
+	// ResetSeconds is how long until the window resets
+	ResetSeconds *int
+}
+
+// Known reports whether the response carried any rate limit headers
+func (r RateLimit) Known() bool {
+	return r.Limit != nil || r.Remaining != nil || r.ResetSeconds != nil
+}
+
+// LastRateLimit returns the rate limit state reported by the most recent response.
+// Every field is unknown until a response with rate limit headers is received, and a
+// response without them keeps the previous state until its window resets.
+func (client *Client) LastRateLimit() RateLimit {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.rateLimit
+}
+
+// Helper function to report whether the window of a rate limit received at the given time is still open
+func windowOpen(limits RateLimit, at, now time.Time) bool {
+	return limits.ResetSeconds != nil && at.Add(time.Duration(*limits.ResetSeconds)*time.Second).After(now)
+}
+
+// Helper function to read the rate limit headers of a response
+func parseRateLimit(header http.Header, now time.Time) RateLimit {
+	limits := RateLimit{
+		Limit:        headerInt(header, rateLimitHeader),
+		Remaining:    headerInt(header, rateLimitRemaining),
+		ResetSeconds: headerInt(header, rateLimitResetAfter),
+	}
+
+	// Some deployments send the reset as a unix timestamp rather than a delay
+	if reset := limits.ResetSeconds; reset != nil && *reset > 1000000000 {
+		seconds := *reset - int(now.Unix())
+		if seconds < 0 {
+			seconds = 0
+		}
+		limits.ResetSeconds = &seconds
+	}
+
+	return limits
+}
+
+// Helper function to parse a non-negative integer header, nil when absent or malformed
+func headerInt(header http.Header, name string) *int {
+	value := header.Get(name)
+	if value == "" {
+		return nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return nil
+	}
+	return &n
+}
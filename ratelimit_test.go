@@ -0,0 +1,112 @@
+package clarifai
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLastRateLimit(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "30")
+		w.Write([]byte(`{"status_code": "OK"}`))
+	})
+
+	if client.LastRateLimit().Known() {
+		t.Error("Rate limit should be unknown before any request")
+	}
+
+	if _, err := client.Info(); err != nil {
+		t.Fatalf("Info() should not return error: %v", err)
+	}
+
+	limits := client.LastRateLimit()
+	if limits.Limit == nil || *limits.Limit != 100 {
+		t.Errorf("Limit should be 100, got %v", limits.Limit)
+	}
+	if limits.Remaining == nil || *limits.Remaining != 0 {
+		t.Errorf("Remaining should be a known zero, got %v", limits.Remaining)
+	}
+	if limits.ResetSeconds == nil || *limits.ResetSeconds != 30 {
+		t.Errorf("ResetSeconds should be 30, got %v", limits.ResetSeconds)
+	}
+}
+
+func TestLastRateLimitMissingHeaders(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(429)
+	})
+
+	client.Info()
+
+	limits := client.LastRateLimit()
+	if limits.Known() || limits.Remaining != nil {
+		t.Errorf("Missing headers should leave the rate limit unknown, got %+v", limits)
+	}
+}
+
+func TestParseRateLimitResetTimestamp(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	header := http.Header{}
+	header.Set("X-RateLimit-Reset", strconv.Itoa(1500000045))
+	header.Set("X-RateLimit-Remaining", "lots")
+
+	limits := parseRateLimit(header, now)
+
+	if limits.ResetSeconds == nil || *limits.ResetSeconds != 45 {
+		t.Errorf("A reset timestamp should become seconds from now, got %v", limits.ResetSeconds)
+	}
+	if limits.Remaining != nil {
+		t.Error("A malformed header should be unknown")
+	}
+}
+
+func TestLastRateLimitKeptWithoutHeaders(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	calls := 0
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls > 1 {
+			w.WriteHeader(429)
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "30")
+		w.Write([]byte(`{"status_code": "OK"}`))
+	})
+
+	client.Info()
+	client.Info()
+
+	limits := client.LastRateLimit()
+	if limits.Remaining == nil || *limits.Remaining != 0 {
+		t.Errorf("A response without headers should keep the open window, got %+v", limits)
+	}
+	if !client.ThrottleState().Exhausted {
+		t.Error("The throttle should still report the exhausted window")
+	}
+}
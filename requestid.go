@@ -0,0 +1,80 @@
+package clarifai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// RequestID returns a stable hash of a request, usable as a cache or idempotency key.
+// Equivalent requests hash identically: nil and empty slices are treated the same.
+// An empty string is returned if the request cannot be serialized.
+func RequestID(req interface{}) string {
+	var kind string
+	var canonical interface{}
+
+	switch r := req.(type) {
+	case TagRequest:
+		kind, canonical = "tag", canonicalTagRequest(r)
+	case *TagRequest:
+		kind, canonical = "tag", canonicalTagRequest(*r)
+	case ColorRequest:
+		kind, canonical = "color", canonicalColorRequest(r)
+	case *ColorRequest:
+		kind, canonical = "color", canonicalColorRequest(*r)
+	default:
+		kind, canonical = fmt.Sprintf("%T", req), req
+	}
+
+	body, err := json.Marshal(canonical)
+
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(append([]byte(kind+"\n"), body...))
+	return hex.EncodeToString(sum[:])
+}
+
+// Helper function to give every field of a tag request a single representation
+func canonicalTagRequest(req TagRequest) interface{} {
+	params := req.Params
+	if len(params) == 0 {
+		params = url.Values{}
+	}
+
+	return struct {
+		URLs        []string   `json:"url"`
+		EncodedData [][]byte   `json:"encoded_data"`
+		LocalIDs    []string   `json:"local_ids"`
+		Model       string     `json:"model"`
+		Language    string     `json:"language"`
+		Params      url.Values `json:"params"`
+		MaxResults  int        `json:"max_results"`
+	}{nonNil(req.URLs), nonNilData(req.EncodedData), nonNil(req.LocalIDs), req.Model, req.Language, params, req.MaxResults}
+}
+
+// Helper function to give every field of a color request a single representation
+func canonicalColorRequest(req ColorRequest) interface{} {
+	return struct {
+		URLs        []string `json:"url"`
+		EncodedData [][]byte `json:"encoded_data"`
+		LocalIDs    []string `json:"local_ids"`
+	}{nonNil(req.URLs), nonNilData(req.EncodedData), nonNil(req.LocalIDs)}
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func nonNilData(d [][]byte) [][]byte {
+	if d == nil {
+		return [][]byte{}
+	}
+	return d
+}
@@ -0,0 +1,30 @@
+package clarifai
+
+import "testing"
+
+func TestRequestIDStable(t *testing.T) {
+	urls := []string{"http://www.clarifai.com/img/metro-north.jpg"}
+
+	a := RequestID(TagRequest{URLs: urls})
+	b := RequestID(&TagRequest{URLs: urls, LocalIDs: []string{}})
+
+	if a == "" || a != b {
+		t.Errorf("RequestID() should hash equivalent requests identically. Got: %v and %v", a, b)
+	}
+}
+
+func TestRequestIDDistinct(t *testing.T) {
+	urls := []string{"http://www.clarifai.com/img/metro-north.jpg"}
+
+	tag := RequestID(TagRequest{URLs: urls})
+	model := RequestID(TagRequest{URLs: urls, Model: "nsfw-v0.1"})
+	color := RequestID(ColorRequest{URLs: urls})
+	limited := RequestID(TagRequest{URLs: urls, MaxResults: 5})
+
+	if tag == model || tag == color {
+		t.Error("RequestID() should differ for different models and endpoints")
+	}
+	if tag == limited {
+		t.Error("RequestID() should differ for different MaxResults")
+	}
+}
@@ -1,15 +1,20 @@
 package clarifai
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
+	"net/url"
+	"strings"
+	"time"
 )
 
 // InfoResp represents the expected JSON response from /info/
 type InfoResp struct {
-	StatusCode    string `json:"status_code"`
-	StatusMessage string `json:"status_msg"`
+	StatusCode    StatusCode `json:"status_code"`
+	StatusMessage string     `json:"status_msg"`
 	Results       struct {
 		MaxImageSize      int     `json:"max_image_size"`
 		DefaultLanguage   string  `json:"default_language"`
@@ -31,12 +36,30 @@ type TagRequest struct {
 	URLs     []string `json:"url"`
 	LocalIDs []string `json:"local_ids,omitempty"`
 	Model    string   `json:"model,omitempty"`
+	Language string   `json:"language,omitempty"`
+
+	// EncodedData holds local images to upload instead of URLs. LocalIDs line up
+	// with the images the same way they do with URLs. Each image is the raw file
+	// bytes, not base64; use DecodeBase64 for data that arrives already encoded.
+	EncodedData [][]byte `json:"-"`
+
+	// MaxResults keeps only the N most probable tags per result; zero keeps them all.
+	// The v1 API has no server-side limit, so tags are sorted by prob and truncated client-side.
+	MaxResults int `json:"-"`
+
+	// Params are sent in the query string rather than the body, for model options
+	// configured that way. Fields that have a body equivalent (url, local_ids, model,
+	// language) must be set on the request itself and are rejected here.
+	Params url.Values `json:"-"`
 }
 
+// tagBodyFields are the TagRequest fields sent in the JSON body
+var tagBodyFields = map[string]bool{"url": true, "local_ids": true, "model": true, "language": true}
+
 // TagResp represents the expected JSON response from /tag/
 type TagResp struct {
-	StatusCode    string `json:"status_code" bson:"status_code"`
-	StatusMessage string `json:"status_msg" bson:"status_msg"`
+	StatusCode    StatusCode `json:"status_code" bson:"status_code"`
+	StatusMessage string     `json:"status_msg" bson:"status_msg"`
 	Meta          struct {
 		Tag struct {
 			Timestamp json.Number `json:"timestamp" bson:"timestamp"`
@@ -45,15 +68,22 @@ type TagResp struct {
 		} `json:"tag" bson:"tag"`
 	} `json:"meta" bson:"meta"`
 	Results []TagResult `json:"results" bson:"results"`
+
+	// Language is the language the tags were ultimately requested in
+	Language string `json:"-" bson:"-"`
+	// LanguageFallback is set when the requested language was replaced by the default language
+	LanguageFallback bool `json:"-" bson:"-"`
+
+	requestedModel string
 }
 
 // TagResult represents the expected data for a single tag result
 type TagResult struct {
-	DocID         *big.Int `json:"docid" bson:"docid"`
-	URL           string   `json:"url" bson:"url"`
-	StatusCode    string   `json:"status_code" bson:"status_code"`
-	StatusMessage string   `json:"status_msg" bson:"status_msg"`
-	LocalID       string   `json:"local_id" bson:"local_id"`
+	DocID         *big.Int   `json:"docid" bson:"docid"`
+	URL           string     `json:"url" bson:"url"`
+	StatusCode    StatusCode `json:"status_code" bson:"status_code"`
+	StatusMessage string     `json:"status_msg" bson:"status_msg"`
+	LocalID       string     `json:"local_id" bson:"local_id"`
 	Result        struct {
 		Tag struct {
 			Classes []string  `json:"classes" bson:"classes"`
@@ -62,24 +92,45 @@ type TagResult struct {
 		} `json:"tag" bson:"tag"`
 	} `json:"result" bson:"result"`
 	DocIDString string `json:"docid_str"`
+	// Model is the model that produced the tags: as reported for the result if the API
+	// does, otherwise the model in the response meta, otherwise the requested model
+	Model string `json:"model,omitempty" bson:"model"`
+	// Provenance identifies the request that produced the result in a merged batch requested WithProvenance
+	Provenance *Provenance `json:"-" bson:"-"`
+
+	probs64   []float64
+	rawCatIDs []string
 }
 
 // ColorRequest represents the JSON request to /color/
 type ColorRequest struct {
 	URLs     []string `json:"url"`
 	LocalIDs []string `json:"local_ids,omitempty"`
+
+	// EncodedData holds local images to upload instead of URLs, as raw file bytes like TagRequest.EncodedData
+	EncodedData [][]byte `json:"-"`
 }
 
 // ColorResp is the expected response from the /color/ endpoint
 type ColorResp struct {
-	StatusCode    string `json:"status_code" bson:"status_code"`
-	StatusMessage string `json:"status_msg" bson:"status_msg"`
-	Results       []struct {
-		DocID       *big.Int `json:"docid" bson:"docid"`
-		URL         string   `json:"url" bson:"url"`
-		DocIDString string   `json:"docid_str" bson:"docid_str"`
-		Colors      []Color  `json:"colors" bson:"colors"`
-	} `json:"results" bson:"results"`
+	StatusCode    StatusCode `json:"status_code" bson:"status_code"`
+	StatusMessage string     `json:"status_msg" bson:"status_msg"`
+	Meta          struct {
+		Color struct {
+			Timestamp json.Number `json:"timestamp" bson:"timestamp"`
+			Model     string      `json:"model" bson:"model"`
+			Config    string      `json:"config" bson:"config"`
+		} `json:"color" bson:"color"`
+	} `json:"meta" bson:"meta"`
+	Results []ColorImage `json:"results" bson:"results"`
+}
+
+// ColorImage represents the colors found in a single image
+type ColorImage struct {
+	DocID       *big.Int `json:"docid" bson:"docid"`
+	URL         string   `json:"url" bson:"url"`
+	DocIDString string   `json:"docid_str" bson:"docid_str"`
+	Colors      []Color  `json:"colors" bson:"colors"`
 }
 
 // Color represents a single color in a given image
@@ -105,13 +156,22 @@ type FeedbackForm struct {
 
 // FeedbackResp is the expected response from /feedback/
 type FeedbackResp struct {
-	StatusCode    string `json:"status_code"`
-	StatusMessage string `json:"status_msg"`
+	StatusCode    StatusCode `json:"status_code"`
+	StatusMessage string     `json:"status_msg"`
 }
 
 // Info will return the current status info for the given client
-func (client *Client) Info() (*InfoResp, error) {
-	res, err := client.commonHTTPRequest(nil, "info", "GET", false)
+func (client *Client) Info(opts ...RequestOption) (*InfoResp, error) {
+	return client.InfoContext(context.Background(), opts...)
+}
+
+// InfoContext is like Info, but the request is bound to ctx
+func (client *Client) InfoContext(ctx context.Context, opts ...RequestOption) (*InfoResp, error) {
+	return client.info(ctx, newRequestOptions(opts))
+}
+
+func (client *Client) info(ctx context.Context, opts *requestOptions) (*InfoResp, error) {
+	res, err := client.commonHTTPRequest(ctx, nil, "info", "GET", false, opts)
 
 	if err != nil {
 		return nil, err
@@ -120,47 +180,249 @@ func (client *Client) Info() (*InfoResp, error) {
 	info := new(InfoResp)
 	err = json.Unmarshal(res, info)
 
-	return info, err
+	if err != nil {
+		return nil, err
+	}
+
+	// Remember the limits so oversized requests can be split without calling /info/ again
+	client.setLimits(infoLimits(info))
+	return info, nil
 }
 
 // Tag allows the client to request tag data on a single, or multiple photos
-func (client *Client) Tag(req TagRequest) (*TagResp, error) {
-	if len(req.URLs) < 1 {
-		return nil, errors.New("Requires at least one url")
+func (client *Client) Tag(req TagRequest, opts ...RequestOption) (*TagResp, error) {
+	return client.TagContext(context.Background(), req, opts...)
+}
+
+// TagContext is like Tag, but the request is bound to ctx
+func (client *Client) TagContext(ctx context.Context, req TagRequest, opts ...RequestOption) (*TagResp, error) {
+	tagres, _, err := client.tagRaw(ctx, req, opts)
+	return tagres, err
+}
+
+// TagRaw is like Tag, but also returns the raw JSON response as sent by Clarifai.
+// When the request is split into chunks or retagged WithFallbackModel there is no
+// single response body, and the raw JSON is the merged TagResp encoded again instead.
+func (client *Client) TagRaw(req TagRequest, opts ...RequestOption) (*TagResp, []byte, error) {
+	return client.tagRaw(context.Background(), req, opts)
+}
+
+func (client *Client) tagRaw(ctx context.Context, req TagRequest, opts []RequestOption) (*TagResp, []byte, error) {
+	o := newRequestOptions(opts)
+	defer o.progress.close()
+
+	if err := validateInputs(req.URLs, req.EncodedData); err != nil {
+		return nil, nil, err
 	}
 
-	res, err := client.commonHTTPRequest(req, "tag", "POST", false)
+	if req.MaxResults < 0 {
+		return nil, nil, errors.New("MaxResults must be positive")
+	}
+
+	for key := range req.Params {
+		if tagBodyFields[key] {
+			return nil, nil, fmt.Errorf("Param %q must be set in the request body", key)
+		}
+	}
+
+	if err := o.ensureIdempotencyKey(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := client.verifyRequestURLs(req.URLs, o); err != nil {
+		return nil, nil, err
+	}
+
+	data, err := transcodeRequestImages(req.EncodedData, o)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	req.EncodedData = data
+
+	tagres, raw, err := client.tagSplit(ctx, req, o)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if o.fallbackModel != "" {
+		if raw, err = client.applyFallbackModel(ctx, req, tagres, raw, o); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := client.processResults(tagres); err != nil {
+		return nil, nil, err
+	}
+
+	return tagres, raw, nil
+}
+
+// Helper function to send a tag request, split into chunks if it is over the batch limit.
+// The raw JSON of a split request is the merged response.
+func (client *Client) tagSplit(ctx context.Context, req TagRequest, opts *requestOptions) (*TagResp, []byte, error) {
+	spans, err := client.requestSpans(req.URLs, req.EncodedData)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(spans) > 1 {
+		return client.tagChunked(ctx, req, spans, opts)
+	}
+
+	var tagres *TagResp
+	var raw []byte
+	opts.progress.track(0, 1, len(req.URLs)+len(req.EncodedData), func() error {
+		tagres, raw, err = client.tagOnce(ctx, req, opts)
+		return err
+	})
+	return tagres, raw, err
+}
+
+// Helper function to send a single tag request, retrying in the default language if asked to
+func (client *Client) tagOnce(ctx context.Context, req TagRequest, opts *requestOptions) (*TagResp, []byte, error) {
+	start := time.Now()
+	tagres, raw, err := client.tag(ctx, req, opts)
+	client.observeBatch(len(req.URLs)+len(req.EncodedData), time.Since(start), err)
+
+	if err != nil && opts.languageFallback && req.Language != "" {
+		return client.tagInDefaultLanguage(ctx, req, opts, err)
+	}
+
+	return tagres, raw, err
+}
+
+func (client *Client) tag(ctx context.Context, req TagRequest, opts *requestOptions) (*TagResp, []byte, error) {
+	endpoint := "tag"
+	if len(req.Params) > 0 {
+		endpoint += "?" + req.Params.Encode()
+	}
+
+	res, err := client.commonHTTPRequest(ctx, client.tagBody(req), endpoint, "POST", false, opts)
+
+	if err != nil {
+		return nil, nil, err
 	}
 
 	tagres := new(TagResp)
 	err = json.Unmarshal(res, tagres)
 
-	return tagres, err
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tagres.Language = req.Language
+	tagres.requestedModel = req.Model
+
+	model := tagres.Meta.Tag.Model
+	if model == "" {
+		model = req.Model
+	}
+	for i := range tagres.Results {
+		if tagres.Results[i].Model == "" {
+			tagres.Results[i].Model = model
+		}
+	}
+	client.logFailedResults(tagres)
+
+	if req.MaxResults > 0 {
+		for i := range tagres.Results {
+			tagres.Results[i].SortByProb()
+			tagres.Results[i].Limit(req.MaxResults)
+		}
+	}
+
+	return tagres, res, nil
 }
 
 // Color makes a request for a series of images to be color tagged
-func (client *Client) Color(req ColorRequest) (*ColorResp, error) {
-	if len(req.URLs) < 1 {
-		return nil, errors.New("Requires at least one url")
+func (client *Client) Color(req ColorRequest, opts ...RequestOption) (*ColorResp, error) {
+	return client.ColorContext(context.Background(), req, opts...)
+}
+
+// ColorContext is like Color, but the request is bound to ctx
+func (client *Client) ColorContext(ctx context.Context, req ColorRequest, opts ...RequestOption) (*ColorResp, error) {
+	colorResponse, _, err := client.colorRaw(ctx, req, opts)
+	return colorResponse, err
+}
+
+// ColorRaw is like Color, but also returns the raw JSON response as sent by Clarifai.
+// When the request is split into chunks, the raw JSON is the merged ColorResp encoded again.
+func (client *Client) ColorRaw(req ColorRequest, opts ...RequestOption) (*ColorResp, []byte, error) {
+	return client.colorRaw(context.Background(), req, opts)
+}
+
+func (client *Client) colorRaw(ctx context.Context, req ColorRequest, opts []RequestOption) (*ColorResp, []byte, error) {
+	o := newRequestOptions(opts)
+	defer o.progress.close()
+
+	if err := validateInputs(req.URLs, req.EncodedData); err != nil {
+		return nil, nil, err
+	}
+
+	if err := o.ensureIdempotencyKey(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := client.verifyRequestURLs(req.URLs, o); err != nil {
+		return nil, nil, err
 	}
 
-	res, err := client.commonHTTPRequest(req, "color", "POST", false)
+	data, err := transcodeRequestImages(req.EncodedData, o)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	req.EncodedData = data
+
+	spans, err := client.requestSpans(req.URLs, req.EncodedData)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(spans) > 1 {
+		return client.colorChunked(ctx, req, spans, o)
+	}
+
+	var colorres *ColorResp
+	var raw []byte
+	o.progress.track(0, 1, len(req.URLs)+len(req.EncodedData), func() error {
+		colorres, raw, err = client.colorOnce(ctx, req, o)
+		return err
+	})
+	return colorres, raw, err
+}
+
+// Helper function to send a single color request
+func (client *Client) colorOnce(ctx context.Context, req ColorRequest, opts *requestOptions) (*ColorResp, []byte, error) {
+	start := time.Now()
+	res, err := client.commonHTTPRequest(ctx, client.colorBody(req), "color", "POST", false, opts)
+	client.observeBatch(len(req.URLs)+len(req.EncodedData), time.Since(start), err)
+
+	if err != nil {
+		return nil, nil, err
 	}
 
 	colorResponse := new(ColorResp)
 	err = json.Unmarshal(res, colorResponse)
 
-	return colorResponse, err
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return colorResponse, res, nil
 }
 
 // Feedback allows the user to provide contextual feedback to Clarifai in order to improve their results
-func (client *Client) Feedback(form FeedbackForm) (*FeedbackResp, error) {
+func (client *Client) Feedback(form FeedbackForm, opts ...RequestOption) (*FeedbackResp, error) {
+	return client.FeedbackContext(context.Background(), form, opts...)
+}
+
+// FeedbackContext is like Feedback, but the request is bound to ctx
+func (client *Client) FeedbackContext(ctx context.Context, form FeedbackForm, opts ...RequestOption) (*FeedbackResp, error) {
 	if form.DocIDs == nil && form.URLs == nil {
 		return nil, errors.New("Requires at least one docid or url")
 	}
@@ -169,7 +431,27 @@ func (client *Client) Feedback(form FeedbackForm) (*FeedbackResp, error) {
 		return nil, errors.New("Request must provide exactly one of the following fields: {'DocIDs', 'URLs'}")
 	}
 
-	res, err := client.commonHTTPRequest(form, "feedback", "POST", false)
+	var err error
+
+	if form.AddTags, err = cleanTags("AddTags", form.AddTags); err != nil {
+		return nil, err
+	}
+
+	if form.RemoveTags, err = cleanTags("RemoveTags", form.RemoveTags); err != nil {
+		return nil, err
+	}
+
+	o := newRequestOptions(opts)
+
+	if err := o.ensureIdempotencyKey(); err != nil {
+		return nil, err
+	}
+
+	res, err := client.commonHTTPRequest(ctx, form, "feedback", "POST", false, o)
+
+	if err != nil {
+		return nil, err
+	}
 
 	feedbackres := new(FeedbackResp)
 	err = json.Unmarshal(res, feedbackres)
@@ -177,3 +459,33 @@ func (client *Client) Feedback(form FeedbackForm) (*FeedbackResp, error) {
 	return feedbackres, err
 
 }
+
+// Helper function to check a request has either urls or encoded images, but not both
+func validateInputs(urls []string, encodedData [][]byte) error {
+	if len(urls) < 1 && len(encodedData) < 1 {
+		return errors.New("Requires at least one url or encoded image")
+	}
+
+	if len(urls) > 0 && len(encodedData) > 0 {
+		return errors.New("Request must provide exactly one of the following fields: {'URLs', 'EncodedData'}")
+	}
+
+	return nil
+}
+
+// Helper function to trim feedback tags, rejecting any that are left empty
+func cleanTags(field string, tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	cleaned := make([]string, len(tags))
+	for i, tag := range tags {
+		cleaned[i] = strings.TrimSpace(tag)
+		if cleaned[i] == "" {
+			return nil, fmt.Errorf("%s[%d] is empty: %q", field, i, tag)
+		}
+	}
+
+	return cleaned, nil
+}
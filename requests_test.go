@@ -1,10 +1,14 @@
 package clarifai
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestInfo(t *testing.T) {
@@ -92,3 +96,227 @@ func TestFeedback(t *testing.T) {
 		t.Errorf("Feedback() should not return error with valid request: %q\n", err)
 	}
 }
+
+func TestFeedbackRejectsEmptyTags(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+
+	feedback := FeedbackForm{
+		URLs:    []string{"http://www.clarifai.com/img/metro-north.jpg"},
+		AddTags: []string{"good", "  "},
+	}
+	_, err := client.Feedback(feedback)
+
+	if err == nil || !strings.Contains(err.Error(), "AddTags[1]") {
+		t.Errorf("Feedback() should reject an empty tag and name it. Got: %v", err)
+	}
+}
+
+func TestCleanTagsTrims(t *testing.T) {
+	tags := []string{" good ", "work"}
+	cleaned, err := cleanTags("AddTags", tags)
+
+	if err != nil {
+		t.Errorf("cleanTags() should not return an err for valid tags: %v", err)
+	}
+
+	if cleaned[0] != "good" || tags[0] != " good " {
+		t.Errorf("cleanTags() should trim a copy of the tags. Got: %q from %q", cleaned, tags)
+	}
+}
+
+func TestTagMaxResults(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":[{"url":"http://www.clarifai.com/img/metro-north.jpg","status_code":"OK","result":{"tag":{"classes":["rail","train","station"],"catids":["836","169","740"],"probs":[0.5,0.9,0.7]}}}]}`)
+	})
+
+	urls := []string{"http://www.clarifai.com/img/metro-north.jpg"}
+	tagres, err := client.Tag(TagRequest{URLs: urls, MaxResults: 2})
+
+	if err != nil {
+		t.Fatalf("Tag() should not return error with valid request: %q\n", err)
+	}
+
+	classes := tagres.Results[0].Result.Tag.Classes
+	if len(classes) != 2 || classes[0] != "train" || classes[1] != "station" {
+		t.Errorf("Tag() should keep the MaxResults most probable tags. Got: %v", classes)
+	}
+	if probs := tagres.Results[0].Probs64(); len(probs) != 2 || probs[0] != 0.9 || probs[1] != 0.7 {
+		t.Errorf("Tag() should keep the full precision probs of the kept tags. Got: %v", probs)
+	}
+
+	_, err = client.Tag(TagRequest{URLs: urls, MaxResults: -1})
+
+	if err == nil {
+		t.Error("Tag() should reject a negative MaxResults")
+	}
+}
+
+func TestTagParams(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	var query url.Values
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":[]}`)
+	})
+
+	urls := []string{"http://www.clarifai.com/img/metro-north.jpg"}
+	_, err := client.Tag(TagRequest{URLs: urls, Params: url.Values{"select_classes": {"train,rail"}}})
+
+	if err != nil {
+		t.Fatalf("Tag() should not return error with valid request: %q\n", err)
+	}
+
+	if query.Get("select_classes") != "train,rail" {
+		t.Errorf("Tag() should send Params in the query string. Got: %v", query)
+	}
+
+	_, err = client.Tag(TagRequest{URLs: urls, Params: url.Values{"model": {"nsfw-v0.1"}}})
+
+	if err == nil {
+		t.Error("Tag() should reject Params that belong in the body")
+	}
+}
+
+func TestTagRaw(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	payload := `{"status_code":"OK","status_msg":"ok","results":[{"url":"http://a","status_code":"OK"}]}`
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, payload)
+	})
+
+	tagres, raw, err := client.TagRaw(TagRequest{URLs: []string{"http://a"}})
+
+	if err != nil {
+		t.Fatalf("TagRaw() should not return error with valid request: %q\n", err)
+	}
+
+	if string(raw) != payload || tagres.Results[0].URL != "http://a" {
+		t.Errorf("TagRaw() should return both the parsed and raw response. Got: %+v, %s", tagres, raw)
+	}
+}
+
+func TestColorRaw(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	payload := `{"status_code":"OK","status_msg":"ok","results":[{"url":"http://a","colors":[{"hex":"#2f4f4f","density":1}]}]}`
+	mux.HandleFunc("/v1/color", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, payload)
+	})
+
+	colorres, raw, err := client.ColorRaw(ColorRequest{URLs: []string{"http://a"}})
+
+	if err != nil {
+		t.Fatalf("ColorRaw() should not return error with valid request: %q\n", err)
+	}
+
+	if string(raw) != payload || colorres.Results[0].Colors[0].Hex != "#2f4f4f" {
+		t.Errorf("ColorRaw() should return both the parsed and raw response. Got: %+v, %s", colorres, raw)
+	}
+}
+
+func TestTagContextDeadline(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":[]}`)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := client.TagContext(ctx, TagRequest{URLs: []string{"http://a"}})
+
+	if err != context.DeadlineExceeded {
+		t.Errorf("TagContext() should return the context's err when it expires. Got: %v", err)
+	}
+}
+
+func TestContextCanceled(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.InfoContext(ctx); err != context.Canceled {
+		t.Errorf("InfoContext() should return context.Canceled. Got: %v", err)
+	}
+
+	if _, err := client.ColorContext(ctx, ColorRequest{URLs: []string{"http://a"}}); err != context.Canceled {
+		t.Errorf("ColorContext() should return context.Canceled. Got: %v", err)
+	}
+
+	form := FeedbackForm{URLs: []string{"http://a"}, AddTags: []string{"good"}}
+	if _, err := client.FeedbackContext(ctx, form); err != context.Canceled {
+		t.Errorf("FeedbackContext() should return context.Canceled. Got: %v", err)
+	}
+}
+
+func TestTagResultModel(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	body := ""
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, body)
+	})
+
+	cases := []struct {
+		body  string
+		model string
+	}{
+		{`{"status_code":"OK","meta":{"tag":{"model":"general-v1.3"}},"results":[{"status_code":"OK"}]}`, "general-v1.3"},
+		{`{"status_code":"OK","meta":{"tag":{"model":"general-v1.3"}},"results":[{"status_code":"OK","model":"nsfw-v1.0"}]}`, "nsfw-v1.0"},
+		{`{"status_code":"OK","results":[{"status_code":"OK"}]}`, "requested"},
+	}
+
+	for _, c := range cases {
+		body = c.body
+		resp, err := client.Tag(TagRequest{URLs: []string{"http://example.com/a.jpg"}, Model: "requested"})
+
+		if err != nil {
+			t.Fatalf("Tag() should not return error: %v", err)
+		}
+		if resp.Results[0].Model != c.model {
+			t.Errorf("Result model should be %q, got %q", c.model, resp.Results[0].Model)
+		}
+	}
+}
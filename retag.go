@@ -0,0 +1,21 @@
+package clarifai
+
+import "errors"
+
+// RetagWithModel tags the urls of a previous response again with a different model,
+// keeping the previous order and local ids
+func (client *Client) RetagWithModel(prev *TagResp, model string, opts ...RequestOption) (*TagResp, error) {
+	if prev == nil || len(prev.Results) == 0 {
+		return nil, errors.New("Requires a previous response with at least one result")
+	}
+
+	items := make([]TagInput, len(prev.Results))
+	for i, result := range prev.Results {
+		items[i] = TagInput{URL: result.URL, LocalID: result.LocalID}
+	}
+
+	req := NewTagRequest(items)
+	req.Model = model
+
+	return client.Tag(req, opts...)
+}
@@ -0,0 +1,37 @@
+package clarifai
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRetagWithModel(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	var sent TagRequest
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":[]}`)
+	})
+
+	prev := &TagResp{Results: []TagResult{{URL: "http://a", LocalID: "1"}, {URL: "http://b", LocalID: "2"}}}
+	_, err := client.RetagWithModel(prev, "nsfw-v0.1")
+
+	if err != nil {
+		t.Fatalf("RetagWithModel() should not return an err: %v", err)
+	}
+
+	if sent.Model != "nsfw-v0.1" || !reflect.DeepEqual(sent.URLs, []string{"http://a", "http://b"}) || !reflect.DeepEqual(sent.LocalIDs, []string{"1", "2"}) {
+		t.Errorf("RetagWithModel() should resend the same inputs with the new model. Got: %+v", sent)
+	}
+}
@@ -0,0 +1,94 @@
+package clarifai
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServerTimingMetric is a single metric of a Server-Timing header, e.g. `db;dur=53;desc="Database"`
+type ServerTimingMetric struct {
+	Name        string
+	Description string
+	// Duration is zero when HasDuration is false
+	Duration    time.Duration
+	HasDuration bool
+}
+
+// ServerTiming is the timing of the most recent call, as reported by the server and as seen by the client
+type ServerTiming struct {
+	// Metrics are the parsed Server-Timing entries, in header order; empty when none were sent
+	Metrics []ServerTimingMetric
+	// Elapsed is the client-measured time from sending the request to receiving the response headers
+	Elapsed time.Duration
+}
+
+// Metric returns the first metric called name
+func (st ServerTiming) Metric(name string) (ServerTimingMetric, bool) {
+	for _, m := range st.Metrics {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return ServerTimingMetric{}, false
+}
+
+// ServerDuration returns the sum of the reported metric durations
+func (st ServerTiming) ServerDuration() time.Duration {
+	var total time.Duration
+	for _, m := range st.Metrics {
+		total += m.Duration
+	}
+	return total
+}
+
+// LastServerTiming returns the timing of the most recent response
+func (client *Client) LastServerTiming() ServerTiming {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.serverTiming
+}
+
+// Helper function to parse every Server-Timing header of a response.
+// Malformed entries and parameters are skipped rather than failing the call.
+func parseServerTiming(header http.Header) []ServerTimingMetric {
+	var metrics []ServerTimingMetric
+
+	for _, value := range header.Values("Server-Timing") {
+		for _, entry := range strings.Split(value, ",") {
+			params := strings.Split(entry, ";")
+			name := strings.TrimSpace(params[0])
+			if name == "" || strings.ContainsAny(name, " \t\"=") {
+				continue
+			}
+
+			metric := ServerTimingMetric{Name: name}
+			for _, param := range params[1:] {
+				kv := strings.SplitN(param, "=", 2)
+				if len(kv) != 2 {
+					continue
+				}
+				key := strings.ToLower(strings.TrimSpace(kv[0]))
+				val := strings.Trim(strings.TrimSpace(kv[1]), `"`)
+
+				switch key {
+				case "dur":
+					ms, err := strconv.ParseFloat(val, 64)
+					if err != nil || ms < 0 || metric.HasDuration {
+						continue
+					}
+					metric.Duration = time.Duration(ms * float64(time.Millisecond))
+					metric.HasDuration = true
+				case "desc":
+					if metric.Description == "" {
+						metric.Description = val
+					}
+				}
+			}
+			metrics = append(metrics, metric)
+		}
+	}
+
+	return metrics
+}
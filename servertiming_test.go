@@ -0,0 +1,57 @@
+package clarifai
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLastServerTiming(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Server-Timing", `db;dur=53, app;dur=47.5;desc="Tagging"`)
+		w.Header().Add("Server-Timing", "cache;desc=miss")
+		w.Write([]byte(`{"status_code": "OK"}`))
+	})
+
+	if _, err := client.Info(); err != nil {
+		t.Fatalf("Info() should not return error: %v", err)
+	}
+
+	timing := client.LastServerTiming()
+
+	if len(timing.Metrics) != 3 {
+		t.Fatalf("Expected 3 metrics, got %+v", timing.Metrics)
+	}
+	app, ok := timing.Metric("app")
+	if !ok || app.Duration != 47500*time.Microsecond || app.Description != "Tagging" {
+		t.Errorf("Unexpected app metric: %+v", app)
+	}
+	if cache, _ := timing.Metric("cache"); cache.HasDuration || cache.Description != "miss" {
+		t.Errorf("A metric without dur should have no duration: %+v", cache)
+	}
+	if timing.ServerDuration() != 100500*time.Microsecond {
+		t.Errorf("ServerDuration() should sum the metrics, got %v", timing.ServerDuration())
+	}
+	if timing.Elapsed <= 0 {
+		t.Error("Elapsed should be measured by the client")
+	}
+}
+
+func TestParseServerTimingMalformed(t *testing.T) {
+	header := http.Header{}
+	header.Set("Server-Timing", `, bad name;dur=1, ok;dur=abc;desc, "quoted";dur=2, neg;dur=-5`)
+
+	metrics := parseServerTiming(header)
+
+	if len(metrics) != 2 || metrics[0].Name != "ok" || metrics[0].HasDuration || metrics[1].Name != "neg" || metrics[1].HasDuration {
+		t.Errorf("Malformed entries and values should be skipped, got %+v", metrics)
+	}
+}
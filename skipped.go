@@ -0,0 +1,184 @@
+package clarifai
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SkippedInput is an input that validation left out of a request
+type SkippedInput struct {
+	// Index is the input's position in the original request
+	Index   int
+	URL     string
+	LocalID string
+	Reason  string
+}
+
+// ValidatedTagResp is a tag response along with the inputs that were not sent
+type ValidatedTagResp struct {
+	*TagResp
+	SkippedInputs []SkippedInput
+}
+
+// ValidatedColorResp is a color response along with the inputs that were not sent
+type ValidatedColorResp struct {
+	*ColorResp
+	SkippedInputs []SkippedInput
+}
+
+// TagValidated is like Tag, but inputs that fail validation are skipped and reported
+// instead of failing the whole request. Empty urls, repeats of an earlier url, images
+// over the MaxImageBytes limit and, when WithURLVerification is given, unreachable
+// urls are skipped. Results hold only the inputs that were sent, in their original order.
+// If every input is skipped no request is made and the response has no results.
+func (client *Client) TagValidated(req TagRequest, opts ...RequestOption) (*ValidatedTagResp, error) {
+	o := newRequestOptions(opts)
+	defer o.progress.close()
+
+	if err := validateInputs(req.URLs, req.EncodedData); err != nil {
+		return nil, err
+	}
+
+	keep, skipped := client.skipInvalidInputs(req.URLs, req.EncodedData, req.LocalIDs, o)
+	validated := &ValidatedTagResp{TagResp: &TagResp{StatusCode: StatusOK}, SkippedInputs: skipped}
+
+	if len(keep) == 0 {
+		return validated, nil
+	}
+
+	req.URLs, req.EncodedData, req.LocalIDs = keptInputs(keep, req.URLs, req.EncodedData, req.LocalIDs)
+
+	tagres, _, err := client.tagRaw(context.Background(), req, append(opts[:len(opts):len(opts)], withoutURLVerification))
+
+	if err != nil {
+		return nil, err
+	}
+
+	validated.TagResp = tagres
+	return validated, nil
+}
+
+// ColorValidated is like Color, but inputs that fail validation are skipped and reported
+// in the same way as TagValidated
+func (client *Client) ColorValidated(req ColorRequest, opts ...RequestOption) (*ValidatedColorResp, error) {
+	o := newRequestOptions(opts)
+	defer o.progress.close()
+
+	if err := validateInputs(req.URLs, req.EncodedData); err != nil {
+		return nil, err
+	}
+
+	keep, skipped := client.skipInvalidInputs(req.URLs, req.EncodedData, req.LocalIDs, o)
+	validated := &ValidatedColorResp{ColorResp: &ColorResp{StatusCode: StatusOK}, SkippedInputs: skipped}
+
+	if len(keep) == 0 {
+		return validated, nil
+	}
+
+	req.URLs, req.EncodedData, req.LocalIDs = keptInputs(keep, req.URLs, req.EncodedData, req.LocalIDs)
+
+	colorres, _, err := client.colorRaw(context.Background(), req, append(opts[:len(opts):len(opts)], withoutURLVerification))
+
+	if err != nil {
+		return nil, err
+	}
+
+	validated.ColorResp = colorres
+	return validated, nil
+}
+
+// withoutURLVerification turns off verification for urls that were already checked
+func withoutURLVerification(o *requestOptions) {
+	o.verifyURLs = false
+}
+
+// Helper function to run every validation check, returning the indexes of the inputs that passed
+func (client *Client) skipInvalidInputs(urls []string, data [][]byte, localIDs []string, opts *requestOptions) ([]int, []SkippedInput) {
+	var keep []int
+	var skipped []SkippedInput
+
+	skip := func(i int, reason string) {
+		input := SkippedInput{Index: i, Reason: reason}
+		if i < len(urls) {
+			input.URL = urls[i]
+		}
+		if len(localIDs) == len(urls)+len(data) {
+			input.LocalID = localIDs[i]
+		}
+		skipped = append(skipped, input)
+	}
+
+	if len(data) > 0 {
+		maxBytes := client.requestLimits().MaxImageBytes
+		for i, image := range data {
+			if size := base64.StdEncoding.EncodedLen(len(image)); maxBytes > 0 && size > maxBytes {
+				skip(i, fmt.Sprintf("image is %d bytes encoded, over the %d byte limit", size, maxBytes))
+				continue
+			}
+			keep = append(keep, i)
+		}
+		return keep, skipped
+	}
+
+	seen := make(map[string]int)
+	var candidates []int
+	for i, u := range urls {
+		if strings.TrimSpace(u) == "" {
+			skip(i, "url is empty")
+			continue
+		}
+		if first, ok := seen[u]; ok {
+			skip(i, fmt.Sprintf("url repeats input %d", first))
+			continue
+		}
+		seen[u] = i
+		candidates = append(candidates, i)
+	}
+
+	if !opts.verifyURLs {
+		return candidates, skipped
+	}
+
+	checkURLs := make([]string, len(candidates))
+	for j, i := range candidates {
+		checkURLs[j] = urls[i]
+	}
+
+	for j, check := range client.VerifyURLs(checkURLs, opts.verifyWorkers, opts.verifyTimeout) {
+		if !check.OK() {
+			skip(candidates[j], "url is unreachable: "+check.reason())
+			continue
+		}
+		keep = append(keep, candidates[j])
+	}
+
+	sort.Slice(skipped, func(a, b int) bool { return skipped[a].Index < skipped[b].Index })
+	return keep, skipped
+}
+
+// Helper function to narrow a request's inputs, and its aligned local ids, to the kept indexes
+func keptInputs(keep []int, urls []string, data [][]byte, localIDs []string) ([]string, [][]byte, []string) {
+	aligned := len(localIDs) == len(urls)+len(data)
+	var keptURLs []string
+	var keptData [][]byte
+	var keptIDs []string
+
+	for _, i := range keep {
+		if len(data) > 0 {
+			keptData = append(keptData, data[i])
+		} else {
+			keptURLs = append(keptURLs, urls[i])
+		}
+		if aligned {
+			keptIDs = append(keptIDs, localIDs[i])
+		}
+	}
+
+	if !aligned {
+		keptIDs = localIDs
+	}
+	return keptURLs, keptData, keptIDs
+}
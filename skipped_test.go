@@ -0,0 +1,95 @@
+package clarifai
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTagValidatedSkipsInvalidURLs(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", echoTagHandler)
+	mux.HandleFunc("/image.jpg", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+	})
+	mux.HandleFunc("/page.html", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+	})
+
+	image := server.URL + "/image.jpg"
+	page := server.URL + "/page.html"
+
+	resp, err := client.TagValidated(TagRequest{
+		URLs:     []string{page, image, " ", image},
+		LocalIDs: []string{"page", "image", "blank", "again"},
+	}, WithURLVerification(2, time.Second))
+
+	if err != nil {
+		t.Fatalf("TagValidated() should not return error: %v", err)
+	}
+
+	if len(resp.Results) != 1 || resp.Results[0].LocalID != "image" {
+		t.Errorf("Only the valid image should be tagged, got %+v", resp.Results)
+	}
+
+	want := []struct {
+		index  int
+		reason string
+	}{{0, "unreachable"}, {2, "empty"}, {3, "repeats input 1"}}
+
+	if len(resp.SkippedInputs) != len(want) {
+		t.Fatalf("Expected %d skipped inputs, got %+v", len(want), resp.SkippedInputs)
+	}
+	for i, w := range want {
+		skipped := resp.SkippedInputs[i]
+		if skipped.Index != w.index || !strings.Contains(skipped.Reason, w.reason) {
+			t.Errorf("Skipped input %d should be index %d for %q, got %+v", i, w.index, w.reason, skipped)
+		}
+	}
+	if resp.SkippedInputs[2].LocalID != "again" {
+		t.Errorf("Skipped inputs should carry their local id, got %q", resp.SkippedInputs[2].LocalID)
+	}
+}
+
+func TestColorValidatedSkipsOversizedImages(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+	client.FallbackLimits.MaxImageBytes = 8
+
+	resp, err := client.ColorValidated(ColorRequest{EncodedData: [][]byte{make([]byte, 64)}})
+
+	if err != nil {
+		t.Fatalf("ColorValidated() should not return error: %v", err)
+	}
+	if len(resp.Results) != 0 || len(resp.SkippedInputs) != 1 {
+		t.Errorf("An oversized image should be skipped without a request, got %+v", resp)
+	}
+}
+
+func TestTagValidatedLeavesOptionsAlone(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	mux.HandleFunc("/v1/tag", echoTagHandler)
+
+	// Spare capacity in the caller's slice must not be written to
+	opts := make([]RequestOption, 0, 1)
+	if _, err := client.TagValidated(TagRequest{URLs: []string{"http://www.clarifai.com/img/metro-north.jpg"}}, opts...); err != nil {
+		t.Fatalf("TagValidated() should not return error: %v", err)
+	}
+
+	if opts[:1][0] != nil {
+		t.Error("TagValidated() should not append to the caller's options")
+	}
+}
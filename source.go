@@ -0,0 +1,66 @@
+package clarifai
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// InputSource provides image data by key, e.g. a path on disk or an object in a
+// storage bucket. Implementations for cloud storage live outside this package.
+type InputSource interface {
+	Open(key string) (io.ReadCloser, error)
+}
+
+// DirSource is an InputSource reading files relative to a local directory
+type DirSource string
+
+// Open opens the file at key, a slash separated path that may not leave the directory
+func (dir DirSource) Open(key string) (io.ReadCloser, error) {
+	if key == "" {
+		return nil, errors.New("Requires a key")
+	}
+
+	for _, part := range strings.Split(key, "/") {
+		if part == ".." {
+			return nil, fmt.Errorf("Key %q leaves the source directory", key)
+		}
+	}
+
+	return os.Open(filepath.Join(string(dir), filepath.FromSlash(key)))
+}
+
+// TagSource reads the images at keys from src and tags them as encoded data.
+// Each key is sent as the local id of its image so results can be matched back.
+func (client *Client) TagSource(src InputSource, keys []string, opts ...RequestOption) (*TagResp, error) {
+	if len(keys) < 1 {
+		return nil, errors.New("Requires at least one key")
+	}
+
+	data := make([][]byte, len(keys))
+	for i, key := range keys {
+		image, err := readSource(src, key)
+		if err != nil {
+			return nil, fmt.Errorf("Reading %q: %v", key, err)
+		}
+		data[i] = image
+	}
+
+	return client.Tag(TagRequest{EncodedData: data, LocalIDs: keys}, opts...)
+}
+
+// Helper function to read a whole image from a source
+func readSource(src InputSource, key string) ([]byte, error) {
+	r, err := src.Open(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
@@ -0,0 +1,70 @@
+package clarifai
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestTagSource(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "cats"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "cats", "tom.jpg"), []byte("tom"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "dog.jpg"), []byte("rex"), 0644)
+
+	var images, localIDs []string
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseMultipartForm(1 << 20)
+		for _, header := range r.MultipartForm.File["encoded_data"] {
+			f, _ := header.Open()
+			data, _ := ioutil.ReadAll(f)
+			images = append(images, string(data))
+		}
+		localIDs = r.MultipartForm.Value["local_id"]
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":[]}`)
+	})
+
+	keys := []string{"cats/tom.jpg", "dog.jpg"}
+	_, err := client.TagSource(DirSource(dir), keys)
+
+	if err != nil {
+		t.Fatalf("TagSource() should not return error: %v", err)
+	}
+
+	expected := []string{base64.StdEncoding.EncodeToString([]byte("tom")), base64.StdEncoding.EncodeToString([]byte("rex"))}
+	if !reflect.DeepEqual(images, expected) {
+		t.Errorf("TagSource() should upload each image in key order, got %v", images)
+	}
+	if !reflect.DeepEqual(localIDs, keys) {
+		t.Errorf("TagSource() should send keys as local ids, got %v", localIDs)
+	}
+}
+
+func TestTagSourceMissingKey(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+
+	if _, err := client.TagSource(DirSource(t.TempDir()), []string{"missing.jpg"}); err == nil {
+		t.Error("TagSource() should fail when a key cannot be read")
+	}
+}
+
+func TestDirSourceRejectsEscapingKeys(t *testing.T) {
+	for _, key := range []string{"", "../secret", "a/../../secret"} {
+		if _, err := DirSource(t.TempDir()).Open(key); err == nil {
+			t.Errorf("Open(%q) should be rejected", key)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package clarifai
+
+import "fmt"
+
+// StatusCode is the status_code reported by Clarifai for a response or a single result.
+// Values not listed below are preserved as-is.
+type StatusCode string
+
+// Known status codes
+const (
+	StatusOK           StatusCode = "OK"
+	StatusPartialError StatusCode = "PARTIAL_ERROR"
+	StatusAllError     StatusCode = "ALL_ERROR"
+	StatusClientError  StatusCode = "CLIENT_ERROR"
+	StatusServerError  StatusCode = "SERVER_ERROR"
+	StatusTokenInvalid StatusCode = "TOKEN_INVALID"
+	StatusTokenExpired StatusCode = "TOKEN_EXPIRED"
+
+	// Reported by the client when the HTTP response carries no status of its own
+	StatusThrottled     StatusCode = "THROTTLED"
+	StatusClarifaiError StatusCode = "CLARIFAI_ERROR"
+	StatusUnexpected    StatusCode = "UNEXPECTED_STATUS_CODE"
+)
+
+// IsError reports whether the status is anything other than OK
+func (code StatusCode) IsError() bool {
+	return code != StatusOK
+}
+
+// String returns the raw status code
+func (code StatusCode) String() string {
+	return string(code)
+}
+
+// ConsistencyCheck cross-checks the top-level status against the per-result statuses,
+// returning an error when they disagree (e.g. OK while every result failed)
+func (resp *TagResp) ConsistencyCheck() error {
+	failed := 0
+	for _, result := range resp.Results {
+		if result.StatusCode.IsError() {
+			failed++
+		}
+	}
+
+	total := len(resp.Results)
+	switch {
+	case resp.StatusCode == StatusOK && total > 0 && failed == total:
+		return fmt.Errorf("Status is %s but all %d results failed", resp.StatusCode, total)
+	case resp.StatusCode == StatusOK && failed > 0:
+		return fmt.Errorf("Status is %s but %d of %d results failed", resp.StatusCode, failed, total)
+	case resp.StatusCode == StatusPartialError && failed == 0:
+		return fmt.Errorf("Status is %s but no results failed", resp.StatusCode)
+	case resp.StatusCode == StatusAllError && total > 0 && failed < total:
+		return fmt.Errorf("Status is %s but %d of %d results succeeded", resp.StatusCode, total-failed, total)
+	}
+
+	return nil
+}
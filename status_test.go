@@ -0,0 +1,54 @@
+package clarifai
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStatusCodeIsError(t *testing.T) {
+	if StatusOK.IsError() {
+		t.Error("StatusOK.IsError() should be false")
+	}
+
+	if !StatusPartialError.IsError() || !StatusCode("SOMETHING_NEW").IsError() {
+		t.Error("IsError() should be true for any non-OK status")
+	}
+}
+
+func TestStatusCodeUnmarshal(t *testing.T) {
+	feedbackres := new(FeedbackResp)
+	err := json.Unmarshal([]byte(`{"status_code":"SOMETHING_NEW","status_msg":"?"}`), feedbackres)
+
+	if err != nil {
+		t.Errorf("StatusCode should decode from a JSON string: %v", err)
+	}
+
+	if feedbackres.StatusCode != "SOMETHING_NEW" {
+		t.Errorf("StatusCode should preserve unknown values. Got: %v", feedbackres.StatusCode)
+	}
+}
+
+func TestConsistencyCheck(t *testing.T) {
+	ok := TagResult{StatusCode: StatusOK}
+	bad := TagResult{StatusCode: StatusClientError}
+
+	cases := []struct {
+		status  StatusCode
+		results []TagResult
+		valid   bool
+	}{
+		{StatusOK, []TagResult{ok, ok}, true},
+		{StatusOK, []TagResult{bad, bad}, false},
+		{StatusPartialError, []TagResult{ok, bad}, true},
+		{StatusPartialError, []TagResult{ok, ok}, false},
+		{StatusAllError, []TagResult{bad}, true},
+		{StatusAllError, []TagResult{ok, bad}, false},
+	}
+
+	for _, c := range cases {
+		err := (&TagResp{StatusCode: c.status, Results: c.results}).ConsistencyCheck()
+		if (err == nil) != c.valid {
+			t.Errorf("ConsistencyCheck() for %s with %d results returned: %v", c.status, len(c.results), err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package clarifai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// TagStreamJSON tags urls one chunk of the batch size at a time and writes every result
+// to w as a line of JSON as soon as its chunk returns, in input order. w is flushed after
+// each result if it is an http.Flusher or has a Flush() error method, like bufio.Writer.
+// The first failed chunk or write stops the stream; results already written are kept.
+func (client *Client) TagStreamJSON(urls []string, w io.Writer, opts ...RequestOption) error {
+	return client.TagStreamJSONContext(context.Background(), urls, w, opts...)
+}
+
+// TagStreamJSONContext is like TagStreamJSON, but every chunk request is bound to ctx
+func (client *Client) TagStreamJSONContext(ctx context.Context, urls []string, w io.Writer, opts ...RequestOption) error {
+	enc := json.NewEncoder(w)
+
+	return client.tagStream(ctx, urls, newRequestOptions(opts), func(result TagResult) error {
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+		return flush(w)
+	})
+}
+
+// TagStream tags urls one chunk of the batch size at a time and sends every result to out,
+// in input order, closing out when it returns. Each chunk waits for the rate limit as with
+// AdaptiveThrottling, whether or not the client enables it, and the next chunk is only
+// requested once out has accepted every result of the current one. A slow consumer therefore
+// slows the requests instead of letting results pile up: at most one chunk of results, up to
+// the batch size, is held in memory beyond what out itself buffers.
+// The first failed chunk or a done ctx stops the stream; results already sent are kept.
+func (client *Client) TagStream(ctx context.Context, urls []string, out chan<- TagResult, opts ...RequestOption) error {
+	defer close(out)
+
+	o := newRequestOptions(opts)
+	o.throttle = true
+
+	return client.tagStream(ctx, urls, o, func(result TagResult) error {
+		select {
+		case out <- result:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// Helper function to tag urls a chunk at a time, handing each result to emit in input order
+// before the next chunk is requested
+func (client *Client) tagStream(ctx context.Context, urls []string, o *requestOptions, emit func(TagResult) error) error {
+	defer o.progress.close()
+
+	if err := validateInputs(urls, nil); err != nil {
+		return err
+	}
+
+	if err := o.ensureIdempotencyKey(); err != nil {
+		return err
+	}
+
+	if err := client.verifyRequestURLs(urls, o); err != nil {
+		return err
+	}
+
+	spans, err := client.requestSpans(urls, nil)
+
+	if err != nil {
+		return err
+	}
+
+	for c, s := range spans {
+		var tagres *TagResp
+		o.progress.track(c, len(spans), s.end-s.start, func() error {
+			tagres, _, err = client.tagOnce(ctx, TagRequest{URLs: urls[s.start:s.end]}, o.withKeySuffix("-"+strconv.Itoa(c)))
+			return err
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if err := client.processResults(tagres); err != nil {
+			return err
+		}
+
+		for _, result := range tagres.Results {
+			if err := emit(result); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Helper function to flush buffered writers so each line is visible as soon as it is written
+func flush(w io.Writer) error {
+	switch f := w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case http.Flusher:
+		f.Flush()
+	}
+	return nil
+}
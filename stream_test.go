@@ -0,0 +1,200 @@
+package clarifai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTagStreamJSON(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 2
+
+	defer server.Close()
+
+	calls := 0
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		echoTagHandler(w, r)
+	})
+
+	var out strings.Builder
+	buf := bufio.NewWriter(&out)
+	urls := []string{"a", "b", "c"}
+
+	if err := client.TagStreamJSON(urls, buf); err != nil {
+		t.Fatalf("TagStreamJSON() should not return error: %v", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("Three urls with a batch size of two should take two requests, took %d", calls)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected one flushed line per result, got %q", out.String())
+	}
+	for i, line := range lines {
+		var result TagResult
+		if err := json.Unmarshal([]byte(line), &result); err != nil || result.URL != urls[i] {
+			t.Errorf("Line %d should be the result for %s, got %s (%v)", i, urls[i], line, err)
+		}
+	}
+}
+
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return 0, errors.New("disk full")
+}
+
+func TestTagStreamJSONWriteError(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 1
+
+	defer server.Close()
+
+	calls := 0
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		echoTagHandler(w, r)
+	})
+
+	w := &failingWriter{}
+	err := client.TagStreamJSON([]string{"a", "b"}, w)
+
+	if err == nil || err.Error() != "disk full" {
+		t.Errorf("TagStreamJSON() should return the write error, got %v", err)
+	}
+	if calls != 1 || w.writes != 1 {
+		t.Errorf("A failed write should abort the stream, got %d calls and %d writes", calls, w.writes)
+	}
+}
+
+func TestTagStreamJSONContextCanceled(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 2
+
+	defer server.Close()
+
+	calls := 0
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		echoTagHandler(w, r)
+	})
+
+	// The context is canceled once the first chunk is written
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := &cancelingWriter{cancel: cancel}
+
+	err := client.TagStreamJSONContext(ctx, []string{"a", "b", "c"}, w)
+
+	if err != context.Canceled {
+		t.Errorf("TagStreamJSONContext() should stop with the context, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("No chunk should be requested after the context is canceled, got %d calls", calls)
+	}
+}
+
+type cancelingWriter struct {
+	cancel context.CancelFunc
+}
+
+func (w *cancelingWriter) Write(p []byte) (int, error) {
+	w.cancel()
+	return len(p), nil
+}
+
+func TestTagStreamBackpressure(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 1
+
+	defer server.Close()
+
+	var calls int32
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		echoTagHandler(w, r)
+	})
+
+	urls := []string{"a", "b", "c"}
+	out := make(chan TagResult)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- client.TagStream(context.Background(), urls, out)
+	}()
+
+	// While the consumer holds back, no more than the chunk waiting to be sent is requested
+	first := <-out
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("A slow consumer should hold back the next chunk, got %d calls", n)
+	}
+
+	got := []string{first.URL}
+	for result := range out {
+		got = append(got, result.URL)
+	}
+
+	if err := <-errs; err != nil {
+		t.Fatalf("TagStream() should not return error: %v", err)
+	}
+	if strings.Join(got, ",") != strings.Join(urls, ",") {
+		t.Errorf("Results should arrive in input order, got %v", got)
+	}
+}
+
+func TestTagStreamThrottles(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.FallbackLimits.MaxBatchSize = 1
+
+	defer server.Close()
+
+	var calls int32
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "60")
+		echoTagHandler(w, r)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	out := make(chan TagResult, 2)
+	err := client.TagStream(ctx, []string{"a", "b"}, out)
+
+	if err != context.DeadlineExceeded {
+		t.Errorf("TagStream() should wait for the exhausted window even without AdaptiveThrottling, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 || len(out) != 1 {
+		t.Errorf("Only the first chunk should be sent, got %d calls and %d results", n, len(out))
+	}
+}
@@ -0,0 +1,267 @@
+package clarifai
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Calibrate applies fn to every tag probability and returns the calibrated values.
+// The raw probabilities in Result.Tag.Probs are left untouched.
+func (result TagResult) Calibrate(fn func(float32) float32) []float32 {
+	probs := result.Result.Tag.Probs
+	calibrated := make([]float32, len(probs))
+
+	for i, prob := range probs {
+		calibrated[i] = fn(prob)
+	}
+
+	return calibrated
+}
+
+// TagRow is a flat, columnar-friendly view of a single tag in a response.
+//
+// Schema (one row per class per result):
+//
+//	url      string  image url as sent in the request
+//	local_id string  caller supplied local id, empty if none
+//	docid    string  Clarifai docid as a hex string (docid_str)
+//	rank     int     position of the tag within its result, starting at 0
+//	class    string  tag name
+//	catid    string  category id, empty if the API did not return one
+//	prob     float32 tag probability
+type TagRow struct {
+	URL     string  `json:"url" bson:"url"`
+	LocalID string  `json:"local_id" bson:"local_id"`
+	DocID   string  `json:"docid" bson:"docid"`
+	Rank    int     `json:"rank" bson:"rank"`
+	Class   string  `json:"class" bson:"class"`
+	CatID   string  `json:"catid" bson:"catid"`
+	Prob    float32 `json:"prob" bson:"prob"`
+}
+
+// Rows flattens every result in the response into one TagRow per tag
+func (resp *TagResp) Rows() []TagRow {
+	var rows []TagRow
+
+	for _, result := range resp.Results {
+		tag := result.Result.Tag
+		for i, class := range tag.Classes {
+			row := TagRow{
+				URL:     result.URL,
+				LocalID: result.LocalID,
+				DocID:   result.DocIDString,
+				Rank:    i,
+				Class:   class,
+			}
+			if i < len(tag.CatIDs) {
+				row.CatID = tag.CatIDs[i]
+			}
+			if i < len(tag.Probs) {
+				row.Prob = tag.Probs[i]
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return rows
+}
+
+// SortByProb orders the tags from most to least probable, keeping classes, catids and probs aligned
+func (result *TagResult) SortByProb() {
+	tag := result.Result.Tag
+	sort.Stable(byProb{tag.Classes, tag.CatIDs, tag.Probs, result.rawCatIDs, result.probs64})
+}
+
+// Limit keeps only the first n tags of the result
+func (result *TagResult) Limit(n int) {
+	tag := &result.Result.Tag
+	if n < len(tag.Classes) {
+		tag.Classes = tag.Classes[:n]
+	}
+	if n < len(tag.CatIDs) {
+		tag.CatIDs = tag.CatIDs[:n]
+	}
+	if n < len(tag.Probs) {
+		tag.Probs = tag.Probs[:n]
+	}
+	if n < len(result.rawCatIDs) {
+		result.rawCatIDs = result.rawCatIDs[:n]
+	}
+	if n < len(result.probs64) {
+		result.probs64 = result.probs64[:n]
+	}
+}
+
+// byProb sorts parallel tag slices by descending prob
+type byProb struct {
+	classes []string
+	catIDs  []string
+	probs   []float32
+	raw     []string
+	probs64 []float64
+}
+
+func (s byProb) Len() int {
+	return len(s.probs)
+}
+
+func (s byProb) Less(i, j int) bool {
+	return s.probs[i] > s.probs[j]
+}
+
+func (s byProb) Swap(i, j int) {
+	s.probs[i], s.probs[j] = s.probs[j], s.probs[i]
+	if i < len(s.classes) && j < len(s.classes) {
+		s.classes[i], s.classes[j] = s.classes[j], s.classes[i]
+	}
+	if i < len(s.catIDs) && j < len(s.catIDs) {
+		s.catIDs[i], s.catIDs[j] = s.catIDs[j], s.catIDs[i]
+	}
+	if i < len(s.raw) && j < len(s.raw) {
+		s.raw[i], s.raw[j] = s.raw[j], s.raw[i]
+	}
+	if i < len(s.probs64) && j < len(s.probs64) {
+		s.probs64[i], s.probs64[j] = s.probs64[j], s.probs64[i]
+	}
+}
+
+// GroupByCatIDPrefix groups classes by the segment of their catid before the first sep.
+// Classes without a catid are grouped under the empty string.
+func (result TagResult) GroupByCatIDPrefix(sep string) map[string][]string {
+	tag := result.Result.Tag
+	groups := make(map[string][]string)
+
+	for i, class := range tag.Classes {
+		prefix := ""
+		if i < len(tag.CatIDs) {
+			prefix = tag.CatIDs[i]
+			if sep != "" {
+				prefix = strings.SplitN(prefix, sep, 2)[0]
+			}
+		}
+		groups[prefix] = append(groups[prefix], class)
+	}
+
+	return groups
+}
+
+// TagCloudEntry is a single class aggregated across a batch
+type TagCloudEntry struct {
+	Class   string
+	Count   int
+	AvgProb float32
+}
+
+// TagCloud counts how many results carry each class at or above minProb, sorted by
+// count then average prob, both descending. Classes below the threshold are not counted.
+func (resp *TagResp) TagCloud(minProb float32) []TagCloudEntry {
+	index := make(map[string]int)
+	var entries []TagCloudEntry
+
+	for _, result := range resp.Results {
+		tag := result.Result.Tag
+		for i, class := range tag.Classes {
+			if i >= len(tag.Probs) || tag.Probs[i] < minProb {
+				continue
+			}
+			j, ok := index[class]
+			if !ok {
+				j = len(entries)
+				index[class] = j
+				entries = append(entries, TagCloudEntry{Class: class})
+			}
+			entries[j].Count++
+			entries[j].AvgProb += tag.Probs[i]
+		}
+	}
+
+	for i := range entries {
+		entries[i].AvgProb /= float32(entries[i].Count)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].AvgProb > entries[j].AvgProb
+	})
+
+	return entries
+}
+
+// Entropy returns the Shannon entropy, in bits, of the result's probs after
+// normalizing them to sum to 1. Higher values mean a less certain prediction.
+func (result TagResult) Entropy() float64 {
+	var total float64
+	for _, prob := range result.Result.Tag.Probs {
+		total += float64(prob)
+	}
+
+	if total <= 0 {
+		return 0
+	}
+
+	var entropy float64
+	for _, prob := range result.Result.Tag.Probs {
+		if prob <= 0 {
+			continue
+		}
+		p := float64(prob) / total
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
+
+// Sample returns a copy of the response holding n results chosen pseudo-randomly
+// from seed, in their original order. The same seed always picks the same results.
+func (resp *TagResp) Sample(n int, seed int64) *TagResp {
+	sample := *resp
+	if n >= len(resp.Results) {
+		sample.Results = append([]TagResult(nil), resp.Results...)
+		return &sample
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	picked := rand.New(rand.NewSource(seed)).Perm(len(resp.Results))[:n]
+	sort.Ints(picked)
+
+	sample.Results = make([]TagResult, n)
+	for i, j := range picked {
+		sample.Results[i] = resp.Results[j]
+	}
+
+	return &sample
+}
+
+// FilterByProb drops the tags whose prob is below minProb, keeping classes, catids and probs aligned
+func (result *TagResult) FilterByProb(minProb float32) {
+	tag := &result.Result.Tag
+	kept := 0
+
+	for i, prob := range tag.Probs {
+		if prob < minProb {
+			continue
+		}
+		tag.Probs[kept] = prob
+		if i < len(tag.Classes) && kept < len(tag.Classes) {
+			tag.Classes[kept] = tag.Classes[i]
+		}
+		if i < len(tag.CatIDs) && kept < len(tag.CatIDs) {
+			tag.CatIDs[kept] = tag.CatIDs[i]
+		}
+		if i < len(result.rawCatIDs) && kept < len(result.rawCatIDs) {
+			result.rawCatIDs[kept] = result.rawCatIDs[i]
+		}
+		if i < len(result.probs64) && kept < len(result.probs64) {
+			result.probs64[kept] = result.probs64[i]
+		}
+		kept++
+	}
+
+	result.Limit(kept)
+}
@@ -0,0 +1,151 @@
+package clarifai
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalibrate(t *testing.T) {
+	result := TagResult{}
+	result.Result.Tag.Probs = []float32{0.5, 0.25}
+
+	calibrated := result.Calibrate(func(p float32) float32 { return p * 2 })
+
+	if len(calibrated) != 2 || calibrated[0] != 1 || calibrated[1] != 0.5 {
+		t.Errorf("Calibrate() should apply fn to every prob. Got: %v", calibrated)
+	}
+
+	if result.Result.Tag.Probs[0] != 0.5 {
+		t.Errorf("Calibrate() should not modify the raw probs. Got: %v", result.Result.Tag.Probs)
+	}
+}
+
+func TestRows(t *testing.T) {
+	result := TagResult{URL: "http://www.clarifai.com/img/metro-north.jpg", DocIDString: "31fdb2316ff87fb5d747554ba5267313"}
+	result.Result.Tag.Classes = []string{"train", "railroad"}
+	result.Result.Tag.CatIDs = []string{"169"}
+	result.Result.Tag.Probs = []float32{0.9, 0.8}
+	resp := &TagResp{Results: []TagResult{result}}
+
+	rows := resp.Rows()
+
+	if len(rows) != 2 {
+		t.Fatalf("Rows() should return one row per tag. Expected: 2, Got: %v", len(rows))
+	}
+
+	if rows[1].Class != "railroad" || rows[1].Rank != 1 || rows[1].Prob != 0.8 || rows[1].CatID != "" {
+		t.Errorf("Rows() returned an unexpected row: %+v", rows[1])
+	}
+
+	if rows[0].DocID != result.DocIDString || rows[0].URL != result.URL {
+		t.Errorf("Rows() should copy result identifiers: %+v", rows[0])
+	}
+}
+
+func TestSortByProbAndLimit(t *testing.T) {
+	result := TagResult{}
+	result.Result.Tag.Classes = []string{"a", "b", "c"}
+	result.Result.Tag.CatIDs = []string{"1", "2", "3"}
+	result.Result.Tag.Probs = []float32{0.2, 0.9, 0.5}
+
+	result.SortByProb()
+	result.Limit(2)
+
+	tag := result.Result.Tag
+	if len(tag.Classes) != 2 || tag.Classes[0] != "b" || tag.Classes[1] != "c" || tag.CatIDs[0] != "2" || tag.Probs[1] != 0.5 {
+		t.Errorf("SortByProb() and Limit() should keep the top tags aligned. Got: %+v", tag)
+	}
+}
+
+func TestGroupByCatIDPrefix(t *testing.T) {
+	result := TagResult{}
+	result.Result.Tag.Classes = []string{"dog", "cat", "car", "unknown"}
+	result.Result.Tag.CatIDs = []string{"animal/1", "animal/2", "vehicle/7"}
+
+	groups := result.GroupByCatIDPrefix("/")
+
+	if len(groups["animal"]) != 2 || groups["animal"][1] != "cat" {
+		t.Errorf("GroupByCatIDPrefix() should group classes sharing a prefix. Got: %v", groups)
+	}
+
+	if len(groups["vehicle"]) != 1 || groups["vehicle"][0] != "car" {
+		t.Errorf("GroupByCatIDPrefix() should keep separate prefixes apart. Got: %v", groups)
+	}
+
+	if len(groups[""]) != 1 || groups[""][0] != "unknown" {
+		t.Errorf("GroupByCatIDPrefix() should group classes without catids under the empty string. Got: %v", groups)
+	}
+}
+
+func TestTagCloud(t *testing.T) {
+	cloud := queryTestResp().TagCloud(0.55)
+
+	if len(cloud) != 3 {
+		t.Fatalf("TagCloud() should skip classes below the threshold. Got: %+v", cloud)
+	}
+
+	if cloud[0].Class != "dog" || cloud[0].Count != 2 || cloud[0].AvgProb != 0.75 {
+		t.Errorf("TagCloud() should put the most frequent class first. Got: %+v", cloud[0])
+	}
+
+	if cloud[1].Class != "sofa" || cloud[2].Class != "grass" {
+		t.Errorf("TagCloud() should order equal counts by average prob. Got: %+v", cloud)
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	uniform := TagResult{}
+	uniform.Result.Tag.Probs = []float32{0.9, 0.9, 0.9, 0.9}
+
+	if e := uniform.Entropy(); math.Abs(e-2) > 1e-9 {
+		t.Errorf("Entropy() of four equal probs should be 2 bits. Got: %v", e)
+	}
+
+	certain := TagResult{}
+	certain.Result.Tag.Probs = []float32{1, 0}
+
+	if e := certain.Entropy(); e != 0 {
+		t.Errorf("Entropy() of a single certain tag should be 0. Got: %v", e)
+	}
+}
+
+func TestSample(t *testing.T) {
+	resp := &TagResp{StatusCode: StatusOK}
+	for i := 0; i < 10; i++ {
+		resp.Results = append(resp.Results, TagResult{LocalID: string(rune('a' + i))})
+	}
+
+	a := resp.Sample(3, 42)
+	b := resp.Sample(3, 42)
+
+	if len(a.Results) != 3 || a.StatusCode != StatusOK {
+		t.Fatalf("Sample() should return n results and keep the response fields. Got: %+v", a)
+	}
+
+	for i := range a.Results {
+		if a.Results[i].LocalID != b.Results[i].LocalID {
+			t.Error("Sample() should be deterministic for a seed")
+		}
+		if i > 0 && a.Results[i].LocalID < a.Results[i-1].LocalID {
+			t.Error("Sample() should keep the original order")
+		}
+	}
+
+	if len(resp.Results) != 10 {
+		t.Error("Sample() should not modify the original response")
+	}
+}
+
+func TestFilterByProb(t *testing.T) {
+	result := TagResult{}
+	result.Result.Tag.Classes = []string{"a", "b", "c"}
+	result.Result.Tag.CatIDs = []string{"1", "2", "3"}
+	result.Result.Tag.Probs = []float32{0.9, 0.2, 0.7}
+
+	result.FilterByProb(0.5)
+
+	tag := result.Result.Tag
+	if len(tag.Probs) != 2 || tag.Classes[1] != "c" || tag.CatIDs[1] != "3" || tag.Probs[1] != 0.7 {
+		t.Errorf("FilterByProb() should drop low tags and keep the rest aligned. Got: %+v", tag)
+	}
+}
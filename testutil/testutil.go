@@ -0,0 +1,96 @@
+// Package testutil provides helpers for testing code built on the Clarifai client
+package testutil
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/clarifai/clarifai-go"
+)
+
+// Option configures which fields AssertTagRespEqual compares
+type Option func(*config)
+
+type config struct {
+	timestamps bool
+	docIDs     bool
+}
+
+// CompareTimestamps makes AssertTagRespEqual compare the meta timestamp, which is ignored by default
+func CompareTimestamps() Option {
+	return func(c *config) {
+		c.timestamps = true
+	}
+}
+
+// CompareDocIDs makes AssertTagRespEqual compare result docids, which are ignored by default
+func CompareDocIDs() Option {
+	return func(c *config) {
+		c.docIDs = true
+	}
+}
+
+// AssertTagRespEqual fails t with one line per differing field when got does not match want.
+// Timestamps and docids change between calls, so they are ignored unless asked for.
+func AssertTagRespEqual(t testing.TB, want, got *clarifai.TagResp, opts ...Option) {
+	t.Helper()
+
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	for _, diff := range diffTagResp(want, got, c) {
+		t.Errorf("TagResp mismatch: %s", diff)
+	}
+}
+
+// Helper function to list the differences between two responses
+func diffTagResp(want, got *clarifai.TagResp, c *config) []string {
+	if want == nil || got == nil {
+		if want != got {
+			return []string{fmt.Sprintf("want %v, got %v", want, got)}
+		}
+		return nil
+	}
+
+	var diffs []string
+	check := func(path string, w, g interface{}) {
+		if !reflect.DeepEqual(w, g) {
+			diffs = append(diffs, fmt.Sprintf("%s: want %#v, got %#v", path, w, g))
+		}
+	}
+
+	check("StatusCode", want.StatusCode, got.StatusCode)
+	check("StatusMessage", want.StatusMessage, got.StatusMessage)
+	check("Meta.Tag.Model", want.Meta.Tag.Model, got.Meta.Tag.Model)
+	check("Meta.Tag.Config", want.Meta.Tag.Config, got.Meta.Tag.Config)
+	if c.timestamps {
+		check("Meta.Tag.Timestamp", want.Meta.Tag.Timestamp, got.Meta.Tag.Timestamp)
+	}
+
+	if len(want.Results) != len(got.Results) {
+		return append(diffs, fmt.Sprintf("len(Results): want %d, got %d", len(want.Results), len(got.Results)))
+	}
+
+	for i := range want.Results {
+		w, g := want.Results[i], got.Results[i]
+		prefix := fmt.Sprintf("Results[%d].", i)
+
+		check(prefix+"URL", w.URL, g.URL)
+		check(prefix+"StatusCode", w.StatusCode, g.StatusCode)
+		check(prefix+"StatusMessage", w.StatusMessage, g.StatusMessage)
+		check(prefix+"LocalID", w.LocalID, g.LocalID)
+		check(prefix+"Model", w.Model, g.Model)
+		check(prefix+"Classes", w.Result.Tag.Classes, g.Result.Tag.Classes)
+		check(prefix+"CatIDs", w.Result.Tag.CatIDs, g.Result.Tag.CatIDs)
+		check(prefix+"Probs", w.Result.Tag.Probs, g.Result.Tag.Probs)
+		if c.docIDs {
+			check(prefix+"DocID", w.DocIDDecimal(), g.DocIDDecimal())
+			check(prefix+"DocIDString", w.DocIDString, g.DocIDString)
+		}
+	}
+
+	return diffs
+}
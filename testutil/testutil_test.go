@@ -0,0 +1,69 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/clarifai/clarifai-go"
+)
+
+// recorder captures failures instead of failing the test
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func tagResp(docID int64, timestamp string, probs ...float32) *clarifai.TagResp {
+	resp := &clarifai.TagResp{StatusCode: clarifai.StatusOK}
+	resp.Meta.Tag.Timestamp = json.Number(timestamp)
+	result := clarifai.TagResult{URL: "http://example.com/a.jpg", StatusCode: clarifai.StatusOK, DocID: big.NewInt(docID)}
+	result.Result.Tag.Classes = []string{"dog"}
+	result.Result.Tag.Probs = probs
+	resp.Results = []clarifai.TagResult{result}
+	return resp
+}
+
+func TestAssertTagRespEqualIgnoresVolatileFields(t *testing.T) {
+	r := &recorder{}
+
+	AssertTagRespEqual(r, tagResp(1, "1443807051", 0.9), tagResp(2, "1443807099", 0.9))
+
+	if len(r.errors) != 0 {
+		t.Errorf("Docids and timestamps should be ignored by default, got %v", r.errors)
+	}
+}
+
+func TestAssertTagRespEqualReportsDiffs(t *testing.T) {
+	r := &recorder{}
+
+	AssertTagRespEqual(r, tagResp(1, "1443807051", 0.9), tagResp(2, "1443807099", 0.5), CompareDocIDs(), CompareTimestamps())
+
+	if len(r.errors) != 3 {
+		t.Fatalf("Expected probs, docid and timestamp to differ, got %v", r.errors)
+	}
+	if !strings.Contains(strings.Join(r.errors, "\n"), "Results[0].Probs") {
+		t.Errorf("Diffs should name the differing field, got %v", r.errors)
+	}
+}
+
+func TestAssertTagRespEqualComparesModel(t *testing.T) {
+	r := &recorder{}
+	want, got := tagResp(1, "1443807051", 0.9), tagResp(1, "1443807051", 0.9)
+	want.Results[0].Model = "general"
+	got.Results[0].Model = "nsfw"
+
+	AssertTagRespEqual(r, want, got)
+
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "Results[0].Model") {
+		t.Errorf("Expected the result model to differ, got %v", r.errors)
+	}
+}
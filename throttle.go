@@ -0,0 +1,100 @@
+package clarifai
+
+import (
+	"context"
+	"time"
+)
+
+// adaptiveThrottleFloor is how many remaining calls count as running low when the limit is unknown
+const adaptiveThrottleFloor = 10
+
+// ThrottleState is how long AdaptiveThrottling would hold the next request
+type ThrottleState struct {
+	// Delay is the pause before the next request; zero when requests are not being slowed
+	Delay time.Duration
+	// Exhausted reports that no calls remain until the rate limit window resets
+	Exhausted bool
+}
+
+// Active reports whether requests are currently being slowed
+func (s ThrottleState) Active() bool {
+	return s.Delay > 0
+}
+
+// ThrottleState returns the current throttle computed from the last rate limit headers.
+// It is reported whether or not AdaptiveThrottling is enabled.
+func (client *Client) ThrottleState() ThrottleState {
+	client.mu.Lock()
+	rateLimit, at := client.rateLimit, client.rateLimitAt
+	client.mu.Unlock()
+
+	return throttleFor(rateLimit, at, time.Now())
+}
+
+// Helper function to compute the throttle for a rate limit received at the given time.
+// Once fewer than a tenth of the calls in the window remain, the time left in the window
+// is spread evenly over them; with none left, requests wait until the window resets.
+func throttleFor(limits RateLimit, at, now time.Time) ThrottleState {
+	if limits.Remaining == nil || limits.ResetSeconds == nil {
+		return ThrottleState{}
+	}
+
+	left := at.Add(time.Duration(*limits.ResetSeconds) * time.Second).Sub(now)
+	if left <= 0 {
+		return ThrottleState{}
+	}
+
+	remaining := *limits.Remaining
+	if remaining == 0 {
+		return ThrottleState{Delay: left, Exhausted: true}
+	}
+
+	floor := adaptiveThrottleFloor
+	if limits.Limit != nil {
+		floor = *limits.Limit / 10
+	}
+	if remaining > floor {
+		return ThrottleState{}
+	}
+
+	return ThrottleState{Delay: left / time.Duration(remaining+1)}
+}
+
+// Helper function to reserve the next send time under the current throttle, returning how long
+// to wait for it. Each reservation moves the next send time on by the throttle delay, so
+// concurrent callers are spread out rather than all sent once the same delay has passed.
+func (client *Client) reserveSend(now time.Time) time.Duration {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	state := throttleFor(client.rateLimit, client.rateLimitAt, now)
+	if !state.Active() {
+		return 0
+	}
+
+	slot := now.Add(state.Delay)
+	if next := client.nextSend.Add(state.Delay); !state.Exhausted && next.After(slot) {
+		slot = next
+	}
+	client.nextSend = slot
+
+	return slot.Sub(now)
+}
+
+// Helper function to hold a request for its reserved send time, giving up if ctx is done first
+func (client *Client) waitForRateLimit(ctx context.Context) error {
+	delay := client.reserveSend(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,92 @@
+package clarifai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestThrottleFor(t *testing.T) {
+	at := time.Unix(1500000000, 0)
+	now := at.Add(10 * time.Second)
+
+	cases := []struct {
+		name   string
+		limits RateLimit
+		state  ThrottleState
+	}{
+		{"unknown", RateLimit{}, ThrottleState{}},
+		{"plenty left", RateLimit{Limit: intPtr(100), Remaining: intPtr(50), ResetSeconds: intPtr(60)}, ThrottleState{}},
+		{"running low", RateLimit{Limit: intPtr(100), Remaining: intPtr(4), ResetSeconds: intPtr(60)}, ThrottleState{Delay: 10 * time.Second}},
+		{"no limit header", RateLimit{Remaining: intPtr(9), ResetSeconds: intPtr(60)}, ThrottleState{Delay: 5 * time.Second}},
+		{"exhausted", RateLimit{Limit: intPtr(100), Remaining: intPtr(0), ResetSeconds: intPtr(60)}, ThrottleState{Delay: 50 * time.Second, Exhausted: true}},
+		{"window over", RateLimit{Limit: intPtr(100), Remaining: intPtr(0), ResetSeconds: intPtr(5)}, ThrottleState{}},
+	}
+
+	for _, c := range cases {
+		if got := throttleFor(c.limits, at, now); got != c.state {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.state, got)
+		}
+	}
+}
+
+func TestAdaptiveThrottlingWaits(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+	client.AdaptiveThrottling = true
+
+	defer server.Close()
+
+	calls := 0
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "60")
+		w.Write([]byte(`{"status_code": "OK"}`))
+	})
+
+	if _, err := client.Info(); err != nil {
+		t.Fatalf("Info() should not return error: %v", err)
+	}
+
+	if state := client.ThrottleState(); !state.Exhausted || !state.Active() {
+		t.Errorf("ThrottleState() should report the exhausted window, got %+v", state)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.InfoContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("InfoContext() should wait for the reset and give up with the context, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("No request should be sent while the limit is exhausted, got %d calls", calls)
+	}
+}
+
+func TestReserveSendSpreadsCallers(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+	now := time.Unix(1500000000, 0)
+	client.rateLimit = RateLimit{Limit: intPtr(100), Remaining: intPtr(4), ResetSeconds: intPtr(50)}
+	client.rateLimitAt = now
+
+	// Three callers arriving together are each given their own slot
+	for i := 1; i <= 3; i++ {
+		if delay := client.reserveSend(now); delay != time.Duration(i)*10*time.Second {
+			t.Errorf("Caller %d should wait %v, got %v", i, time.Duration(i)*10*time.Second, delay)
+		}
+	}
+
+	// Once the window is over requests go straight out
+	if delay := client.reserveSend(now.Add(time.Minute)); delay != 0 {
+		t.Errorf("Requests should not wait after the window resets, got %v", delay)
+	}
+}
@@ -0,0 +1,109 @@
+package clarifai
+
+import "sync"
+
+// ClientOption configures a Client in NewClient
+type ClientOption func(*Client)
+
+// TokenSource supplies access tokens. A single source can be shared by many
+// clients using the same credentials so that a token is refreshed only once.
+type TokenSource interface {
+	// Token returns the current access token, requesting one if there is none yet
+	Token() (string, error)
+	// Refresh replaces the stale token with a new one. If the stale token was
+	// already replaced by another caller, the newer token is returned as is.
+	Refresh(stale string) (string, error)
+}
+
+// WithTokenSource makes the client take its access tokens from ts instead of
+// requesting its own. By default every client manages its own token.
+func WithTokenSource(ts TokenSource) ClientOption {
+	return func(client *Client) {
+		client.tokenSource = ts
+	}
+}
+
+// sharedTokenSource requests tokens with one client's credentials and hands them out to many
+type sharedTokenSource struct {
+	mu     sync.Mutex
+	client *Client
+	token  string
+}
+
+// NewTokenSource returns a TokenSource, safe for concurrent use, that requests
+// tokens with the credentials, API root and http.Client of client
+func NewTokenSource(client *Client) TokenSource {
+	fetcher := NewClient(client.ClientID, client.ClientSecret)
+	fetcher.APIRoot = client.APIRoot
+	fetcher.HTTPClient = client.HTTPClient
+
+	return &sharedTokenSource{client: fetcher}
+}
+
+func (ts *sharedTokenSource) Token() (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token != "" {
+		return ts.token, nil
+	}
+	return ts.fetch()
+}
+
+func (ts *sharedTokenSource) Refresh(stale string) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token != "" && ts.token != stale {
+		return ts.token, nil
+	}
+	return ts.fetch()
+}
+
+// fetch must be called with ts.mu held
+func (ts *sharedTokenSource) fetch() (string, error) {
+	if err := ts.client.requestAccessToken(); err != nil {
+		return "", err
+	}
+	ts.token = ts.client.CurrentAccessToken()
+	return ts.token, nil
+}
+
+// Helper function to get the token to send, from the token source if one is set
+func (client *Client) currentToken() (string, error) {
+	if client.tokenSource == nil {
+		return client.CurrentAccessToken(), nil
+	}
+
+	token, err := client.tokenSource.Token()
+
+	if err != nil {
+		return "", err
+	}
+
+	client.setAccessToken(token)
+	return token, nil
+}
+
+// Helper function to replace a rejected token, through the token source if one is set
+func (client *Client) refreshToken(stale string) error {
+	if client.tokenSource == nil {
+		client.refreshMu.Lock()
+		defer client.refreshMu.Unlock()
+
+		// Another caller already replaced the stale token
+		if client.CurrentAccessToken() != stale {
+			return nil
+		}
+		return client.requestAccessToken()
+	}
+
+	token, err := client.tokenSource.Refresh(stale)
+
+	if err != nil {
+		return err
+	}
+
+	client.setAccessToken(token)
+	return nil
+}
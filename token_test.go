@@ -0,0 +1,81 @@
+package clarifai
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSharedTokenSource(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+
+	refreshes := 0
+	mux.HandleFunc("/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		refreshes++
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":36000,"scope": "api_access", "token_type": "Bearer"}`, refreshes)
+	})
+
+	// The server only accepts the second token, forcing one refresh
+	mux.HandleFunc("/v1/info", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(401)
+			return
+		}
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":{}}`)
+	})
+
+	base := NewClient(ClientID, ClientSecret)
+	base.setAPIRoot(server.URL)
+	ts := NewTokenSource(base)
+
+	for i := 0; i < 3; i++ {
+		client := NewClient(ClientID, ClientSecret, WithTokenSource(ts))
+		client.setAPIRoot(server.URL)
+
+		_, err := client.Info()
+
+		if err != nil {
+			t.Fatalf("Info() should succeed with a shared token source: %v", err)
+		}
+
+		if client.AccessToken != "token-2" {
+			t.Errorf("Info() should keep the client token in sync. Got: %v", client.AccessToken)
+		}
+	}
+
+	if refreshes != 2 {
+		t.Errorf("Clients sharing a token source should only request tokens once per refresh. Got: %v", refreshes)
+	}
+}
+
+func TestTokenSourceRefreshStale(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+
+	refreshes := 0
+	mux.HandleFunc("/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		refreshes++
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `{"access_token":"token-%d"}`, refreshes)
+	})
+
+	base := NewClient(ClientID, ClientSecret)
+	base.setAPIRoot(server.URL)
+	ts := NewTokenSource(base)
+
+	stale, _ := ts.Token()
+	first, _ := ts.Refresh(stale)
+	second, _ := ts.Refresh(stale)
+
+	if first != "token-2" || second != "token-2" || refreshes != 2 {
+		t.Errorf("Refresh() should not refresh a token that was already replaced. Got: %v, %v after %v requests", first, second, refreshes)
+	}
+}
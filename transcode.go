@@ -0,0 +1,81 @@
+package clarifai
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	// Registered so GIF images can be decoded for transcoding
+	_ "image/gif"
+)
+
+// ImageFormat is a format local images can be transcoded to before they are uploaded
+type ImageFormat string
+
+// The formats images can be transcoded to
+const (
+	FormatJPEG ImageFormat = "jpeg"
+	FormatPNG  ImageFormat = "png"
+)
+
+// WithTranscode converts every EncodedData image that is not already in format to it
+// before upload, e.g. to shrink large PNGs below MaxImageBytes as JPEG. quality is the
+// JPEG quality from 1 to 100, zero meaning jpeg.DefaultQuality; it is ignored for PNG.
+// Only JPEG, PNG and GIF images can be decoded. Any other image, such as HEIC or BMP,
+// fails the request with an error naming it rather than being sent as is.
+func WithTranscode(format ImageFormat, quality int) RequestOption {
+	return func(o *requestOptions) {
+		o.transcodeFormat = format
+		o.transcodeQuality = quality
+	}
+}
+
+// Helper function to transcode a request's images if it was asked WithTranscode
+func transcodeRequestImages(images [][]byte, o *requestOptions) ([][]byte, error) {
+	if o.transcodeFormat == "" || len(images) == 0 {
+		return images, nil
+	}
+	return transcodeImages(images, o.transcodeFormat, o.transcodeQuality)
+}
+
+// Helper function to transcode images to format, leaving the caller's slice untouched.
+// Images already in format are kept byte for byte.
+func transcodeImages(images [][]byte, format ImageFormat, quality int) ([][]byte, error) {
+	if format != FormatJPEG && format != FormatPNG {
+		return nil, fmt.Errorf("Cannot transcode to %q, only to jpeg or png", format)
+	}
+
+	if quality == 0 {
+		quality = jpeg.DefaultQuality
+	}
+
+	out := make([][]byte, len(images))
+	for i, data := range images {
+		img, name, err := image.Decode(bytes.NewReader(data))
+
+		if err != nil {
+			return nil, fmt.Errorf("Image %d cannot be decoded for transcoding, only JPEG, PNG and GIF can: %v", i, err)
+		}
+
+		if ImageFormat(name) == format {
+			out[i] = data
+			continue
+		}
+
+		buf := new(bytes.Buffer)
+		if format == FormatJPEG {
+			err = jpeg.Encode(buf, img, &jpeg.Options{Quality: quality})
+		} else {
+			err = png.Encode(buf, img)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Image %d could not be transcoded to %s: %v", i, format, err)
+		}
+		out[i] = buf.Bytes()
+	}
+
+	return out, nil
+}
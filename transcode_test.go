@@ -0,0 +1,91 @@
+package clarifai
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func encodedImage(t *testing.T, format ImageFormat) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(1, 1, color.RGBA{R: 255, A: 255})
+
+	buf := new(bytes.Buffer)
+	var err error
+	if format == FormatJPEG {
+		err = jpeg.Encode(buf, img, nil)
+	} else {
+		err = png.Encode(buf, img)
+	}
+	if err != nil {
+		t.Fatalf("Encoding the test image should not fail: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestTagTranscode(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	var formats []string
+	var uploaded [][]byte
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseMultipartForm(1 << 20)
+		for _, header := range r.MultipartForm.File["encoded_data"] {
+			f, _ := header.Open()
+			encoded, _ := ioutil.ReadAll(f)
+			data, _ := base64.StdEncoding.DecodeString(string(encoded))
+			_, format, _ := image.DecodeConfig(bytes.NewReader(data))
+			formats = append(formats, format)
+			uploaded = append(uploaded, data)
+		}
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":[]}`)
+	})
+
+	pngData, jpegData := encodedImage(t, FormatPNG), encodedImage(t, FormatJPEG)
+	data := [][]byte{pngData, jpegData}
+
+	if _, err := client.Tag(TagRequest{EncodedData: data}, WithTranscode(FormatJPEG, 80)); err != nil {
+		t.Fatalf("Tag() should not return error: %v", err)
+	}
+
+	if len(formats) != 2 || formats[0] != "jpeg" || formats[1] != "jpeg" {
+		t.Errorf("Every image should be uploaded as JPEG, got %v", formats)
+	}
+	if len(uploaded) == 2 && !bytes.Equal(uploaded[1], jpegData) {
+		t.Error("An image already in the target format should be sent unchanged")
+	}
+	if !bytes.Equal(data[0], pngData) {
+		t.Error("The caller's images should not be replaced")
+	}
+}
+
+func TestTranscodeUndecodable(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+
+	// A BMP header, which the standard library cannot decode
+	bmp := append([]byte("BM"), make([]byte, 52)...)
+
+	_, err := client.Tag(TagRequest{EncodedData: [][]byte{bmp}}, WithTranscode(FormatJPEG, 0))
+
+	if err == nil || !strings.Contains(err.Error(), "Image 0 cannot be decoded") {
+		t.Errorf("Tag() should report the image it cannot decode, got %v", err)
+	}
+
+	if _, err := transcodeImages([][]byte{encodedImage(t, FormatPNG)}, "webp", 0); err == nil {
+		t.Error("transcodeImages() should reject a format it cannot encode")
+	}
+}
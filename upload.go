@@ -0,0 +1,83 @@
+package clarifai
+
+import (
+	"bytes"
+	"encoding/base64"
+	"mime/multipart"
+	"net/url"
+	"strconv"
+)
+
+// DefaultUploadField is the multipart field images are uploaded in by default.
+// The v1 API, which this client targets, reads images from "encoded_data"; v2 takes
+// images inline in a JSON body and has no multipart field. Gateways that proxy the
+// upload under another name, such as "files", can be reached WithUploadField.
+const DefaultUploadField = "encoded_data"
+
+// WithUploadField makes the client upload local images in the multipart field name
+// instead of DefaultUploadField. An empty name keeps the default.
+func WithUploadField(name string) ClientOption {
+	return func(client *Client) {
+		client.uploadField = name
+	}
+}
+
+// Helper function to get the multipart field images are uploaded in
+func (client *Client) uploadFieldName() string {
+	if client.uploadField == "" {
+		return DefaultUploadField
+	}
+	return client.uploadField
+}
+
+// multipartBody is a request body sent as multipart/form-data rather than JSON,
+// used when uploading local images
+type multipartBody struct {
+	fields url.Values
+	files  [][]byte
+}
+
+// Helper function to build the upload form for a set of encoded images and their local ids
+func newMultipartBody(files [][]byte, localIDs []string) multipartBody {
+	form := multipartBody{fields: url.Values{}, files: files}
+	for _, id := range localIDs {
+		form.fields.Add("local_id", id)
+	}
+	return form
+}
+
+// encode writes the fields, then one base64 part per image in order under field
+func (form multipartBody) encode(field string) ([]byte, string, error) {
+	buf := new(bytes.Buffer)
+	writer := multipart.NewWriter(buf)
+
+	for key, values := range form.fields {
+		for _, value := range values {
+			if err := writer.WriteField(key, value); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	for i, file := range form.files {
+		part, err := writer.CreateFormFile(field, "image_"+strconv.Itoa(i))
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		encoder := base64.NewEncoder(base64.StdEncoding, part)
+		if _, err := encoder.Write(file); err != nil {
+			return nil, "", err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
@@ -0,0 +1,119 @@
+package clarifai
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestTagEncodedData(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	var images []string
+	var localIDs []string
+	var model string
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("Tag() should send a multipart form for encoded data: %v", err)
+		}
+		for _, header := range r.MultipartForm.File["encoded_data"] {
+			f, _ := header.Open()
+			data, _ := ioutil.ReadAll(f)
+			images = append(images, string(data))
+		}
+		localIDs = r.MultipartForm.Value["local_id"]
+		model = r.FormValue("model")
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":[]}`)
+	})
+
+	data := [][]byte{[]byte("first image"), []byte("second image")}
+	_, err := client.Tag(TagRequest{EncodedData: data, LocalIDs: []string{"1", "2"}, Model: "default"})
+
+	if err != nil {
+		t.Fatalf("Tag() should not return error with encoded data: %q\n", err)
+	}
+
+	expected := []string{base64.StdEncoding.EncodeToString(data[0]), base64.StdEncoding.EncodeToString(data[1])}
+	if !reflect.DeepEqual(images, expected) {
+		t.Errorf("Tag() should upload each image base64 encoded in order. Got: %v", images)
+	}
+
+	if !reflect.DeepEqual(localIDs, []string{"1", "2"}) || model != "default" {
+		t.Errorf("Tag() should send local ids and model alongside the images. Got: %v, %v", localIDs, model)
+	}
+}
+
+func TestColorEncodedData(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	uploaded := 0
+	mux.HandleFunc("/v1/color", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseMultipartForm(1 << 20)
+		uploaded = len(r.MultipartForm.File["encoded_data"])
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":[]}`)
+	})
+
+	_, err := client.Color(ColorRequest{EncodedData: [][]byte{[]byte("image")}})
+
+	if err != nil || uploaded != 1 {
+		t.Errorf("Color() should upload encoded data. Got: %v uploads, %v", uploaded, err)
+	}
+}
+
+func TestUploadField(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret, WithUploadField("files"))
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	var fields []string
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseMultipartForm(1 << 20)
+		for field := range r.MultipartForm.File {
+			fields = append(fields, field)
+		}
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":[]}`)
+	})
+
+	if _, err := client.Tag(TagRequest{EncodedData: [][]byte{[]byte("image")}}); err != nil {
+		t.Fatalf("Tag() should not return error: %v", err)
+	}
+
+	if !reflect.DeepEqual(fields, []string{"files"}) {
+		t.Errorf("Images should be uploaded in the configured field. Got: %v", fields)
+	}
+	if client.Config().UploadField != "files" {
+		t.Errorf("Config() should report the upload field, got %q", client.Config().UploadField)
+	}
+}
+
+func TestEncodedDataValidation(t *testing.T) {
+	client := NewClient(ClientID, ClientSecret)
+
+	if _, err := client.Tag(TagRequest{}); err == nil {
+		t.Error("Tag() should reject a request with neither urls nor encoded data")
+	}
+
+	if _, err := client.Color(ColorRequest{URLs: []string{"http://a"}, EncodedData: [][]byte{{1}}}); err == nil {
+		t.Error("Color() should reject a request with both urls and encoded data")
+	}
+}
@@ -0,0 +1,18 @@
+package clarifai
+
+// Operation estimates follow Clarifai's billing rules for the v1 API:
+// every image sent to /tag/ or /color/ is one operation, whether by url or
+// upload, regardless of how many tags or colors come back. Each request
+// carries a single model, so a tag request costs one operation per image.
+// The v1 responses do not report actual usage, so these estimates are the
+// only figures available client side.
+
+// Operations estimates how many billable operations the tag request will consume
+func (req TagRequest) Operations() int {
+	return len(req.URLs) + len(req.EncodedData)
+}
+
+// Operations estimates how many billable operations the color request will consume
+func (req ColorRequest) Operations() int {
+	return len(req.URLs) + len(req.EncodedData)
+}
@@ -0,0 +1,15 @@
+package clarifai
+
+import "testing"
+
+func TestOperations(t *testing.T) {
+	urls := []string{"http://www.clarifai.com/img/metro-north.jpg", "http://www.clarifai.com/img/metro-north.jpg"}
+
+	if ops := (TagRequest{URLs: urls, Model: "default"}).Operations(); ops != 2 {
+		t.Errorf("TagRequest.Operations() should count one operation per url. Expected: 2, Got: %v", ops)
+	}
+
+	if ops := (ColorRequest{URLs: urls[:1]}).Operations(); ops != 1 {
+		t.Errorf("ColorRequest.Operations() should count one operation per url. Expected: 1, Got: %v", ops)
+	}
+}
@@ -0,0 +1,122 @@
+package clarifai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// URLCheck is the outcome of checking a single url with a HEAD request
+type URLCheck struct {
+	URL         string
+	StatusCode  int
+	ContentType string
+	Err         error
+}
+
+// OK reports whether the url was reachable and served an image
+func (check URLCheck) OK() bool {
+	return check.Err == nil && check.StatusCode >= 200 && check.StatusCode < 300 && strings.HasPrefix(check.ContentType, "image/")
+}
+
+// URLCheckError is returned when one or more urls fail verification
+type URLCheckError struct {
+	Failed []URLCheck
+}
+
+func (e *URLCheckError) Error() string {
+	parts := make([]string, len(e.Failed))
+	for i, check := range e.Failed {
+		parts[i] = check.URL + ": " + check.reason()
+	}
+	return "Unreachable urls: " + strings.Join(parts, "; ")
+}
+
+// Helper function to describe why a url failed its check
+func (check URLCheck) reason() string {
+	switch {
+	case check.Err != nil:
+		return check.Err.Error()
+	case check.StatusCode < 200 || check.StatusCode >= 300:
+		return fmt.Sprintf("status %d", check.StatusCode)
+	default:
+		return fmt.Sprintf("content type %q", check.ContentType)
+	}
+}
+
+// VerifyURLs issues a HEAD request to every url using the client's http.Client,
+// at most concurrency at a time, each bounded by timeout. Results are in input order.
+func (client *Client) VerifyURLs(urls []string, concurrency int, timeout time.Duration) []URLCheck {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	checks := make([]URLCheck, len(urls))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, u := range urls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, u string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			checks[i] = client.checkURL(u, timeout)
+		}(i, u)
+	}
+
+	wg.Wait()
+	return checks
+}
+
+func (client *Client) checkURL(u string, timeout time.Duration) URLCheck {
+	check := URLCheck{URL: u}
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequest("HEAD", u, nil)
+
+	if err != nil {
+		check.Err = err
+		return check
+	}
+
+	res, err := client.httpClient().Do(req.WithContext(ctx))
+
+	if err != nil {
+		check.Err = err
+		return check
+	}
+
+	res.Body.Close()
+	check.StatusCode = res.StatusCode
+	check.ContentType = res.Header.Get("Content-Type")
+	return check
+}
+
+// Helper function to run the opt-in url verification for a request
+func (client *Client) verifyRequestURLs(urls []string, opts *requestOptions) error {
+	if !opts.verifyURLs {
+		return nil
+	}
+
+	var failed []URLCheck
+	for _, check := range client.VerifyURLs(urls, opts.verifyWorkers, opts.verifyTimeout) {
+		if !check.OK() {
+			failed = append(failed, check)
+		}
+	}
+
+	if failed != nil {
+		return &URLCheckError{Failed: failed}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package clarifai
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerifyURLs(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	defer server.Close()
+
+	mux.HandleFunc("/image.jpg", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.WriteHeader(200)
+	})
+
+	mux.HandleFunc("/page.html", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(200)
+	})
+
+	client := NewClient(ClientID, ClientSecret)
+	urls := []string{server.URL + "/image.jpg", server.URL + "/page.html", server.URL + "/missing.jpg"}
+	checks := client.VerifyURLs(urls, 2, time.Second)
+
+	if len(checks) != 3 || !checks[0].OK() || checks[1].OK() || checks[2].OK() {
+		t.Errorf("VerifyURLs() should only accept reachable images. Got: %+v", checks)
+	}
+
+	if checks[2].StatusCode != 404 || checks[2].URL != urls[2] {
+		t.Errorf("VerifyURLs() should report results in input order. Got: %+v", checks[2])
+	}
+}
+
+func TestTagWithURLVerification(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := NewClient(ClientID, ClientSecret)
+	client.setAPIRoot(server.URL)
+
+	defer server.Close()
+
+	tagged := false
+	mux.HandleFunc("/v1/tag", func(w http.ResponseWriter, r *http.Request) {
+		tagged = true
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"status_code":"OK","status_msg":"ok","results":[]}`)
+	})
+
+	_, err := client.Tag(TagRequest{URLs: []string{server.URL + "/missing.jpg"}}, WithURLVerification(4, time.Second))
+
+	if _, ok := err.(*URLCheckError); !ok {
+		t.Errorf("Tag() should return a *URLCheckError for an unreachable url. Got: %v", err)
+	}
+
+	if tagged {
+		t.Error("Tag() should not call the API when url verification fails")
+	}
+}
@@ -0,0 +1,25 @@
+package clarifai
+
+import "testing"
+
+func TestW3CColorsValid(t *testing.T) {
+	for name, hex := range W3CColors {
+		if _, err := parseHexColor(hex); err != nil {
+			t.Errorf("W3CColors[%q] should be a valid hex color. Got: %q", name, hex)
+		}
+	}
+}
+
+func TestNearestW3C(t *testing.T) {
+	name, err := NearestW3C("#2e4e4e")
+
+	if err != nil || name != "DarkSlateGray" {
+		t.Errorf("NearestW3C() should return the closest palette name. Got: %v, %v", name, err)
+	}
+
+	name, _ = Color{Hex: "#00ffff"}.NearestW3C()
+
+	if name != "Aqua" {
+		t.Errorf("NearestW3C() should break ties alphabetically. Got: %v", name)
+	}
+}
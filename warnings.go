@@ -0,0 +1,37 @@
+package clarifai
+
+import "fmt"
+
+// Warnings lists soft issues with a successful response: a language or model
+// other than the one requested, and individual results that failed
+func (resp *TagResp) Warnings() []string {
+	var warnings []string
+
+	if resp.LanguageFallback {
+		warnings = append(warnings, fmt.Sprintf("Requested language was rejected, tags are in %q", resp.Language))
+	}
+
+	model := resp.Meta.Tag.Model
+	if resp.requestedModel != "" && model != "" && model != resp.requestedModel {
+		warnings = append(warnings, fmt.Sprintf("Requested model %q was served by %q", resp.requestedModel, model))
+	}
+
+	for i, result := range resp.Results {
+		if result.StatusCode.IsError() {
+			warnings = append(warnings, fmt.Sprintf("Result %d (%s) failed: %s %s", i, result.URL, result.StatusCode, result.StatusMessage))
+		}
+	}
+
+	return warnings
+}
+
+// Warnings lists soft issues with a successful response, such as a partial failure
+func (resp *ColorResp) Warnings() []string {
+	var warnings []string
+
+	if resp.StatusCode == StatusPartialError {
+		warnings = append(warnings, fmt.Sprintf("Some images failed: %s", resp.StatusMessage))
+	}
+
+	return warnings
+}
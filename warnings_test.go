@@ -0,0 +1,25 @@
+package clarifai
+
+import "testing"
+
+func TestTagRespWarnings(t *testing.T) {
+	resp := &TagResp{requestedModel: "general-v1.3", Language: "en", LanguageFallback: true}
+	resp.Meta.Tag.Model = "default"
+	resp.Results = []TagResult{{URL: "http://a", StatusCode: StatusOK}, {URL: "http://b", StatusCode: StatusClientError}}
+
+	warnings := resp.Warnings()
+
+	if len(warnings) != 3 {
+		t.Errorf("Warnings() should report the fallback, the model substitution and the failed result. Got: %q", warnings)
+	}
+}
+
+func TestTagRespNoWarnings(t *testing.T) {
+	resp := &TagResp{requestedModel: "default"}
+	resp.Meta.Tag.Model = "default"
+	resp.Results = []TagResult{{URL: "http://a", StatusCode: StatusOK}}
+
+	if warnings := resp.Warnings(); warnings != nil {
+		t.Errorf("Warnings() should be empty for a clean response. Got: %q", warnings)
+	}
+}